@@ -0,0 +1,40 @@
+package animation
+
+import "math"
+
+// Easing maps linear progress in [0, 1] to eased progress
+type Easing func(t float32) float32
+
+func Linear(t float32) float32 {
+	return t
+}
+
+func EaseInQuad(t float32) float32 {
+	return t * t
+}
+
+func EaseOutQuad(t float32) float32 {
+	return t * (2 - t)
+}
+
+func EaseInOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	f := 2*t - 2
+	return 0.5*f*f*f + 1
+}
+
+// EaseOutBack overshoots the target a little before settling
+func EaseOutBack(t float32) float32 {
+	const overshoot = 1.70158
+	f := t - 1
+	return f*f*((overshoot+1)*f+overshoot) + 1
+}
+
+func EaseOutElastic(t float32) float32 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return float32(math.Pow(2, -10*float64(t))*math.Sin((float64(t)-0.075)*2*math.Pi/0.3)) + 1
+}
@@ -0,0 +1,68 @@
+package animation
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Particle struct {
+	Position mgl32.Vec2
+	Velocity mgl32.Vec2
+	Color    mgl32.Vec4
+	Size     float32
+	Age      float32
+	Life     float32
+}
+
+// Emitter simulates particles on the CPU, sizes and speeds are in cells
+type Emitter struct {
+	Particles []Particle
+	Gravity   mgl32.Vec2
+	// Fraction of the velocity lost per second
+	Drag float32
+	rand *rand.Rand
+}
+
+func NewEmitter(seed int64) *Emitter {
+	return &Emitter{
+		Gravity: mgl32.Vec2{0, -3},
+		Drag:    1.5,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Burst spreads count particles in all directions from position
+func (emitter *Emitter) Burst(position mgl32.Vec2, count int, color mgl32.Vec4, speed, life float32) {
+	for i := 0; i < count; i++ {
+		angle := emitter.rand.Float64() * 2 * math.Pi
+		particleSpeed := speed * (0.5 + emitter.rand.Float32())
+		emitter.Particles = append(emitter.Particles, Particle{
+			Position: position,
+			Velocity: mgl32.Vec2{
+				float32(math.Cos(angle)) * particleSpeed,
+				float32(math.Sin(angle)) * particleSpeed,
+			},
+			Color: color,
+			Size:  0.2 + 0.2*emitter.rand.Float32(),
+			Life:  life * (0.7 + 0.6*emitter.rand.Float32()),
+		})
+	}
+}
+
+// Update moves the particles by dt seconds and drops the dead ones
+func (emitter *Emitter) Update(dt float32) {
+	alive := emitter.Particles[:0]
+	for _, particle := range emitter.Particles {
+		particle.Age += dt
+		if particle.Age >= particle.Life {
+			continue
+		}
+		damping := float32(math.Max(0, float64(1-emitter.Drag*dt)))
+		particle.Velocity = particle.Velocity.Add(emitter.Gravity.Mul(dt)).Mul(damping)
+		particle.Position = particle.Position.Add(particle.Velocity.Mul(dt))
+		alive = append(alive, particle)
+	}
+	emitter.Particles = alive
+}
@@ -0,0 +1,139 @@
+package animation
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Sprite used for particles, a white dot tinted with the particle color
+const ParticleSprite = "particle"
+
+// System runs the feedback animations of game events.
+// Positions are cell coords, like the snake body.
+type System struct {
+	animations []SpriteAnimation
+	Emitter    *Emitter
+	lastUpdate float64
+}
+
+func NewSystem(seed int64) *System {
+	return &System{Emitter: NewEmitter(seed)}
+}
+
+func (system *System) Add(anim SpriteAnimation) {
+	system.animations = append(system.animations, anim)
+}
+
+// Update advances the particles and drops finished animations
+func (system *System) Update(now float64) {
+	dt := float32(now - system.lastUpdate)
+	if system.lastUpdate == 0 || dt < 0 || dt > 0.1 {
+		// First frame or a long stall, don't let particles jump
+		dt = 0
+	}
+	system.lastUpdate = now
+	system.Emitter.Update(dt)
+
+	running := system.animations[:0]
+	for _, anim := range system.animations {
+		if !anim.Done(now) {
+			running = append(running, anim)
+		}
+	}
+	system.animations = running
+}
+
+func (system *System) Clear() {
+	system.animations = nil
+	system.Emitter.Particles = nil
+}
+
+// FoodEaten pops the food sprite and sprinkles red crumbs
+func (system *System) FoodEaten(sprite string, position mgl32.Vec2, now float64) {
+	system.Add(SpriteAnimation{
+		Sprite: sprite,
+		X:      Constant(position.X()),
+		Y:      Constant(position.Y()),
+		Scale:  NewTween(1, 2, now, 0.3, EaseOutQuad),
+		Alpha:  NewTween(1, 0, now, 0.3, Linear),
+		Color:  mgl32.Vec3{1, 1, 1},
+	})
+	system.Emitter.Burst(position, 12, mgl32.Vec4{1, 0.3, 0.2, 1}, 3, 0.5)
+}
+
+// PowerUp swells the power-up sprite inside a ring of white sparks
+func (system *System) PowerUp(sprite string, position mgl32.Vec2, now float64) {
+	system.Add(SpriteAnimation{
+		Sprite: sprite,
+		X:      Constant(position.X()),
+		Y:      Constant(position.Y()),
+		Scale:  NewTween(1, 2.5, now, 0.4, EaseOutBack),
+		Alpha:  NewTween(1, 0, now, 0.4, EaseInQuad),
+		Color:  mgl32.Vec3{1, 1, 1},
+	})
+	system.Emitter.Burst(position, 20, mgl32.Vec4{1, 1, 1, 1}, 4, 0.6)
+}
+
+// Poison sours the sprite green as it fades
+func (system *System) Poison(sprite string, position mgl32.Vec2, now float64) {
+	system.Add(SpriteAnimation{
+		Sprite: sprite,
+		X:      Constant(position.X()),
+		Y:      Constant(position.Y()),
+		Scale:  NewTween(1, 0.2, now, 0.4, EaseInQuad),
+		Alpha:  NewTween(1, 0, now, 0.4, Linear),
+		Color:  mgl32.Vec3{0.5, 1, 0.3},
+	})
+	system.Emitter.Burst(position, 16, mgl32.Vec4{0.4, 0.9, 0.2, 1}, 2, 0.8)
+}
+
+// Teleport flashes sparks at both portal ends
+func (system *System) Teleport(from, to mgl32.Vec2, now float64) {
+	system.Emitter.Burst(from, 12, mgl32.Vec4{0.5, 0.8, 1, 1}, 3, 0.4)
+	system.Emitter.Burst(to, 12, mgl32.Vec4{0.5, 0.8, 1, 1}, 3, 0.4)
+}
+
+// LevelUp fires golden confetti from the board center
+func (system *System) LevelUp(center mgl32.Vec2, now float64) {
+	system.Emitter.Burst(center, 60, mgl32.Vec4{1, 0.85, 0.2, 1}, 6, 1.2)
+}
+
+// Death swells the head and scatters it
+func (system *System) Death(position mgl32.Vec2, now float64) {
+	system.Add(SpriteAnimation{
+		Sprite: "head",
+		X:      Constant(position.X()),
+		Y:      Constant(position.Y()),
+		Scale:  NewTween(1, 3, now, 0.6, EaseOutBack),
+		Alpha:  NewTween(1, 0, now, 0.6, EaseInQuad),
+		Color:  mgl32.Vec3{1, 0.3, 0.3},
+	})
+	system.Emitter.Burst(position, 40, mgl32.Vec4{0.9, 0.1, 0.1, 1}, 5, 1)
+}
+
+// RivalDeath scatters a crashed rival head in the rival colour
+func (system *System) RivalDeath(position mgl32.Vec2, color mgl32.Vec3, now float64) {
+	system.Add(SpriteAnimation{
+		Sprite: "head",
+		X:      Constant(position.X()),
+		Y:      Constant(position.Y()),
+		Scale:  NewTween(1, 2, now, 0.4, EaseOutQuad),
+		Alpha:  NewTween(1, 0, now, 0.4, EaseInQuad),
+		Color:  color,
+	})
+	system.Emitter.Burst(position, 20, color.Vec4(1), 4, 0.7)
+}
+
+// Draw passes every animated sprite with its cell coords, scale and tint
+func (system *System) Draw(now float64, draw func(sprite string, position mgl32.Vec2, scale float32, tint mgl32.Vec4)) {
+	for _, anim := range system.animations {
+		position := mgl32.Vec2{anim.X.Value(now), anim.Y.Value(now)}
+		tint := anim.Color.Vec4(anim.Alpha.Value(now))
+		draw(anim.Sprite, position, anim.Scale.Value(now), tint)
+	}
+	for _, particle := range system.Emitter.Particles {
+		// Fade out over the particle life
+		tint := particle.Color
+		tint[3] *= 1 - particle.Age/particle.Life
+		draw(ParticleSprite, particle.Position, particle.Size, tint)
+	}
+}
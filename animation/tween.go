@@ -0,0 +1,56 @@
+package animation
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Tween interpolates a value from From to To over Duration seconds
+type Tween struct {
+	From     float32
+	To       float32
+	Start    float64
+	Duration float64
+	Ease     Easing
+}
+
+func NewTween(from, to float32, start, duration float64, ease Easing) Tween {
+	return Tween{From: from, To: to, Start: start, Duration: duration, Ease: ease}
+}
+
+// Constant is a tween that always returns value
+func Constant(value float32) Tween {
+	return Tween{From: value, To: value}
+}
+
+func (tween Tween) progress(now float64) float32 {
+	if tween.Duration <= 0 || now >= tween.Start+tween.Duration {
+		return 1
+	}
+	if now <= tween.Start {
+		return 0
+	}
+	return float32((now - tween.Start) / tween.Duration)
+}
+
+func (tween Tween) Value(now float64) float32 {
+	t := tween.progress(now)
+	if tween.Ease != nil {
+		t = tween.Ease(t)
+	}
+	return tween.From + (tween.To-tween.From)*t
+}
+
+func (tween Tween) Done(now float64) bool {
+	return now >= tween.Start+tween.Duration
+}
+
+// SpriteAnimation moves, scales and fades one sprite
+type SpriteAnimation struct {
+	Sprite string
+	X, Y   Tween
+	Scale  Tween
+	Alpha  Tween
+	Color  mgl32.Vec3
+}
+
+func (anim *SpriteAnimation) Done(now float64) bool {
+	return anim.X.Done(now) && anim.Y.Done(now) && anim.Scale.Done(now) && anim.Alpha.Done(now)
+}
@@ -0,0 +1,102 @@
+package assets
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"snakegame/helpers"
+)
+
+// Default art and shaders, used when no override is found on disk
+//
+//go:embed *.png shaders puzzles
+var embedded embed.FS
+
+// Directories checked, in order, before the embedded assets
+var searchPath []string
+
+// SetSearchPath replaces the override directories, empty ones are skipped
+func SetSearchPath(dirs ...string) {
+	searchPath = searchPath[:0]
+	for _, dir := range dirs {
+		if dir != "" {
+			searchPath = append(searchPath, dir)
+		}
+	}
+}
+
+// ConfigDir is the per user override directory
+func ConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "snakegame", "assets")
+}
+
+// Find returns the override file on disk, or "" for embedded assets
+func Find(name string) string {
+	for _, dir := range searchPath {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Open returns the first asset named name in the search path
+// or the embedded default
+func Open(name string) (io.ReadCloser, error) {
+	for _, dir := range searchPath {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	f, err := embedded.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("asset %s: %w", name, err)
+	}
+	return f, nil
+}
+
+func LoadImage(name string) ([]uint8, int32, int32, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer f.Close()
+	imgBytes, width, height, err := helpers.DecodeImage(f)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("asset %s: %w", name, err)
+	}
+	return imgBytes, width, height, nil
+}
+
+// Checkerboard is a magenta and black RGBA image, hard to miss on screen
+func Checkerboard(size, cellSize int) ([]uint8, int32, int32) {
+	imgBytes := make([]uint8, size*size*4)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			i := (y*size + x) * 4
+			if (x/cellSize+y/cellSize)%2 == 0 {
+				imgBytes[i], imgBytes[i+2] = 255, 255
+			}
+			imgBytes[i+3] = 255
+		}
+	}
+	return imgBytes, int32(size), int32(size)
+}
+
+// SearchPath returns the override directories in lookup order
+func SearchPath() []string {
+	return append([]string(nil), searchPath...)
+}
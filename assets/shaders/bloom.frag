@@ -0,0 +1,26 @@
+#version 410
+// Glow around strongly red pixels, which is the food in the default theme
+in vec2 texCoord;
+
+out vec4 FragmentColor;
+
+uniform sampler2D texture1;
+uniform vec2 resolution;
+
+float glowAmount(vec3 color) {
+    return clamp((color.r - max(color.g, color.b) - 0.4)*2.0, 0.0, 1.0);
+}
+
+void main() {
+    vec2 texel = 3.0/resolution;
+    vec3 glow = vec3(0.0);
+    for (int x = -4; x <= 4; x++) {
+        for (int y = -4; y <= 4; y++) {
+            vec3 sampled = texture(texture1, texCoord + vec2(x, y)*texel).rgb;
+            float weight = exp(-float(x*x + y*y)/8.0);
+            glow += sampled*glowAmount(sampled)*weight;
+        }
+    }
+    vec3 color = texture(texture1, texCoord).rgb;
+    FragmentColor = vec4(color + glow*0.15, 1.0);
+}
@@ -0,0 +1,15 @@
+#version 410
+// Last pass to the window, whitens the frame while flashing
+in vec2 texCoord;
+
+out vec4 FragmentColor;
+
+uniform sampler2D texture1;
+uniform float flash;
+// Dims the letterbox backdrop
+uniform float brightness;
+
+void main() {
+    vec3 color = texture(texture1, texCoord).rgb*brightness;
+    FragmentColor = vec4(mix(color, vec3(1.0), flash), 1.0);
+}
@@ -0,0 +1,22 @@
+#version 410
+// Old monitor look: barrel distortion, scanlines and vignette
+in vec2 texCoord;
+
+out vec4 FragmentColor;
+
+uniform sampler2D texture1;
+uniform vec2 resolution;
+
+void main() {
+    vec2 centered = texCoord*2.0 - 1.0;
+    centered *= 1.0 + dot(centered, centered)*0.04;
+    vec2 uv = centered*0.5 + 0.5;
+    if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+        FragmentColor = vec4(0.0, 0.0, 0.0, 1.0);
+        return;
+    }
+    vec3 color = texture(texture1, uv).rgb;
+    color *= 0.8 + 0.2*sin(uv.y*resolution.y*3.14159);
+    color *= 1.0 - 0.3*dot(centered, centered);
+    FragmentColor = vec4(color, 1.0);
+}
@@ -0,0 +1,14 @@
+#version 410
+// Fullscreen pass over the unit quad
+layout (location = 0) in vec3 aPos;
+layout (location = 1) in vec2 aTexCoord;
+
+out vec2 texCoord;
+
+uniform vec2 offset;
+
+void main()
+{
+    gl_Position = vec4(aPos.xy*2.0 - 1.0 + offset, 0.0, 1.0);
+    texCoord = aTexCoord;
+}
@@ -0,0 +1,11 @@
+#version 410
+in vec2 texCoord;
+in vec4 tint;
+
+out vec4 FragmentColor;
+
+uniform sampler2D texture1;
+
+void main() {
+    FragmentColor=texture(texture1, texCoord)*tint;
+}
@@ -0,0 +1,17 @@
+#version 410
+layout (location = 0) in vec3 aPos;
+layout (location = 1) in vec2 aTexCoord;
+// Per instance attributes, the matrix takes locations 2-5
+layout (location = 2) in mat4 aTransform;
+layout (location = 6) in vec4 aUVRect;
+layout (location = 7) in vec4 aTint;
+
+out vec2 texCoord;
+out vec4 tint;
+
+void main()
+{
+    gl_Position = aTransform*vec4(aPos.x, aPos.y, aPos.z, 1.0);
+    texCoord = aUVRect.xy + aTexCoord*aUVRect.zw;
+    tint = aTint;
+}
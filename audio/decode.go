@@ -0,0 +1,34 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Decoder reads a whole sound file
+type Decoder func(r io.Reader) (*Sound, error)
+
+var decoders = map[string]Decoder{
+	".wav": DecodeWAV,
+	".ogg": DecodeOgg,
+}
+
+// RegisterDecoder adds or replaces the decoder for a file extension
+func RegisterDecoder(extension string, decoder Decoder) {
+	decoders[strings.ToLower(extension)] = decoder
+}
+
+// Decode picks the decoder by the file name extension
+func Decode(name string, r io.Reader) (*Sound, error) {
+	decoder, ok := decoders[strings.ToLower(filepath.Ext(name))]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown sound format", name)
+	}
+	sound, err := decoder(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return sound, nil
+}
@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testdata/test.ogg is a second of mono sound from the oggvorbis tests,
+// the samples below come from its reference decoding
+func TestDecodeOgg(t *testing.T) {
+	f, err := os.Open("testdata/test.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sound, err := Decode("test.ogg", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sound.SampleRate != 44100 || sound.Channels != 1 || sound.Frames() != 44100 {
+		t.Fatalf("%d Hz, %d channels, %d frames, want a second of mono at 44100 Hz",
+			sound.SampleRate, sound.Channels, sound.Frames())
+	}
+	want := map[int]float32{1000: 0.73016357421875, 5000: 0, 11025: -0.32611083984375, 22050: 0, 33075: 0.08172607421875}
+	for i, sample := range want {
+		if got := sound.Samples[i]; got-sample > 0.00002 || sample-got > 0.00002 {
+			t.Errorf("sample %d = %v, want %v", i, got, sample)
+		}
+	}
+}
+
+func TestDecodeRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty.ogg", ""},
+		{"text.ogg", "not an ogg file at all"},
+		{"empty.wav", ""},
+		{"sound.mp3", "ID3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Decode(test.name, strings.NewReader(test.data))
+			if err == nil {
+				t.Error("decoded")
+			}
+		})
+	}
+}
+
+func TestWAVRoundTrip(t *testing.T) {
+	sound := &Sound{SampleRate: 22050, Channels: 2, Samples: []float32{0, 0.5, -0.5, 1, -1, 0.25}}
+	var file bytes.Buffer
+	err := EncodeWAV(&file, sound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode("sound.wav", &file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SampleRate != sound.SampleRate || decoded.Channels != sound.Channels || len(decoded.Samples) != len(sound.Samples) {
+		t.Fatalf("decoded %d Hz, %d channels, %d samples", decoded.SampleRate, decoded.Channels, len(decoded.Samples))
+	}
+	for i, sample := range sound.Samples {
+		if got := decoded.Samples[i]; got-sample > 0.001 || sample-got > 0.001 {
+			t.Errorf("sample %d = %v, want %v", i, got, sample)
+		}
+	}
+}
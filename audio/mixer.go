@@ -0,0 +1,204 @@
+package audio
+
+import (
+	"sync"
+)
+
+// Bus groups voices sharing a gain, like all the effects
+type Bus int
+
+const (
+	SFX Bus = iota
+	Music
+	busCount
+)
+
+// Effects beyond the limit replace the oldest effect,
+// music doesn't count towards it and is never cut off
+const MaxVoices = 32
+
+// Voice is a playing sound
+type Voice struct {
+	sound    *Sound
+	position int
+	gain     float32
+	bus      Bus
+	loop     bool
+	stopped  bool
+}
+
+// Mixer adds up the playing voices into stereo samples,
+// safe to use from the game loop while an output pulls samples
+type Mixer struct {
+	mu         sync.Mutex
+	sampleRate int
+	voices     []*Voice
+	volume     float32
+	busGain    [busCount]float32
+	muted      bool
+}
+
+func NewMixer(sampleRate int) *Mixer {
+	mixer := Mixer{sampleRate: sampleRate, volume: 1}
+	for i := range mixer.busGain {
+		mixer.busGain[i] = 1
+	}
+	return &mixer
+}
+
+func (mixer *Mixer) SampleRate() int {
+	return mixer.sampleRate
+}
+
+// Prepare converts a sound to the mixer format ahead of playing it
+func (mixer *Mixer) Prepare(sound *Sound) *Sound {
+	return sound.stereo(mixer.sampleRate)
+}
+
+// Play starts the sound once
+func (mixer *Mixer) Play(sound *Sound, bus Bus, gain float32) *Voice {
+	return mixer.start(sound, bus, gain, false)
+}
+
+// Loop plays the sound until the voice is stopped
+func (mixer *Mixer) Loop(sound *Sound, bus Bus, gain float32) *Voice {
+	return mixer.start(sound, bus, gain, true)
+}
+
+func (mixer *Mixer) start(sound *Sound, bus Bus, gain float32, loop bool) *Voice {
+	voice := &Voice{sound: mixer.Prepare(sound), gain: gain, bus: bus, loop: loop}
+	mixer.mu.Lock()
+	defer mixer.mu.Unlock()
+	if bus == SFX && mixer.effects() >= MaxVoices {
+		for i, playing := range mixer.voices {
+			if playing.bus == SFX {
+				playing.stopped = true
+				mixer.voices = append(mixer.voices[:i], mixer.voices[i+1:]...)
+				break
+			}
+		}
+	}
+	mixer.voices = append(mixer.voices, voice)
+	return voice
+}
+
+// effects counts the voices on the effects bus
+func (mixer *Mixer) effects() int {
+	count := 0
+	for _, voice := range mixer.voices {
+		if voice.bus == SFX {
+			count++
+		}
+	}
+	return count
+}
+
+// Stop silences the voice, it is dropped on the next mix
+func (mixer *Mixer) Stop(voice *Voice) {
+	if voice == nil {
+		return
+	}
+	mixer.mu.Lock()
+	voice.stopped = true
+	mixer.mu.Unlock()
+}
+
+// StopBus stops every voice on the bus
+func (mixer *Mixer) StopBus(bus Bus) {
+	mixer.mu.Lock()
+	for _, voice := range mixer.voices {
+		if voice.bus == bus {
+			voice.stopped = true
+		}
+	}
+	mixer.mu.Unlock()
+}
+
+// Playing counts the voices still sounding
+func (mixer *Mixer) Playing() int {
+	mixer.mu.Lock()
+	defer mixer.mu.Unlock()
+	count := 0
+	for _, voice := range mixer.voices {
+		if !voice.stopped {
+			count++
+		}
+	}
+	return count
+}
+
+// SetVolume sets the master volume, 1 leaves samples as they are
+func (mixer *Mixer) SetVolume(volume float32) {
+	mixer.mu.Lock()
+	mixer.volume = volume
+	mixer.mu.Unlock()
+}
+
+func (mixer *Mixer) SetBusGain(bus Bus, gain float32) {
+	mixer.mu.Lock()
+	mixer.busGain[bus] = gain
+	mixer.mu.Unlock()
+}
+
+// SetMuted silences the output, voices keep playing
+func (mixer *Mixer) SetMuted(muted bool) {
+	mixer.mu.Lock()
+	mixer.muted = muted
+	mixer.mu.Unlock()
+}
+
+func (mixer *Mixer) Muted() bool {
+	mixer.mu.Lock()
+	defer mixer.mu.Unlock()
+	return mixer.muted
+}
+
+// Mix fills out with interleaved stereo samples and advances the voices
+func (mixer *Mixer) Mix(out []float32) {
+	for i := range out {
+		out[i] = 0
+	}
+	mixer.mu.Lock()
+	defer mixer.mu.Unlock()
+
+	playing := mixer.voices[:0]
+	for _, voice := range mixer.voices {
+		if voice.stopped {
+			continue
+		}
+		gain := voice.gain * mixer.busGain[voice.bus] * mixer.volume
+		if mixer.muted {
+			gain = 0
+		}
+		samples := voice.sound.Samples
+		for i := 0; i < len(out); i++ {
+			if voice.position >= len(samples) {
+				if !voice.loop || len(samples) == 0 {
+					voice.stopped = true
+					break
+				}
+				voice.position = 0
+			}
+			out[i] += samples[voice.position] * gain
+			voice.position++
+		}
+		if voice.position >= len(samples) && !voice.loop {
+			voice.stopped = true
+		}
+		if !voice.stopped {
+			playing = append(playing, voice)
+		}
+	}
+	for i := len(playing); i < len(mixer.voices); i++ {
+		mixer.voices[i] = nil
+	}
+	mixer.voices = playing
+
+	for i, sample := range out {
+		if sample > 1 {
+			out[i] = 1
+		} else if sample < -1 {
+			out[i] = -1
+		}
+	}
+}
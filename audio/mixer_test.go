@@ -0,0 +1,199 @@
+package audio
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// tone is a stereo sound at the mixer rate holding the value
+func tone(frames int, value float32) *Sound {
+	samples := make([]float32, frames*2)
+	for i := range samples {
+		samples[i] = value
+	}
+	return &Sound{SampleRate: 1000, Channels: 2, Samples: samples}
+}
+
+// recorder keeps what is written to it
+type recorder struct {
+	samples []float32
+}
+
+func (output *recorder) Write(samples []float32) error {
+	output.samples = append(output.samples, samples...)
+	return nil
+}
+
+func (output *recorder) Close() error {
+	return nil
+}
+
+func TestVoicesEnd(t *testing.T) {
+	mixer := NewMixer(1000)
+	once := mixer.Play(tone(100, 0.1), SFX, 1)
+	mixer.Loop(tone(100, 0.1), Music, 1)
+	err := Render(mixer, NullOutput{}, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mixer.Playing() != 2 {
+		t.Errorf("%d voices playing halfway through, want 2", mixer.Playing())
+	}
+	Render(mixer, NullOutput{}, 0.2)
+	if !once.stopped || mixer.Playing() != 1 {
+		t.Errorf("one shot stopped %v with %d voices playing, want true and the loop", once.stopped, mixer.Playing())
+	}
+	mixer.StopBus(Music)
+	Render(mixer, NullOutput{}, 0.01)
+	if mixer.Playing() != 0 || len(mixer.voices) != 0 {
+		t.Errorf("%d voices left after stopping the music", len(mixer.voices))
+	}
+}
+
+func TestMusicOutlastsTheVoiceLimit(t *testing.T) {
+	mixer := NewMixer(1000)
+	music := mixer.Loop(tone(10, 0.1), Music, 1)
+	first := mixer.Play(tone(1000, 0), SFX, 1)
+	for i := 0; i < MaxVoices+5; i++ {
+		mixer.Play(tone(1000, 0), SFX, 1)
+	}
+	if music.stopped {
+		t.Error("music was dropped for an effect")
+	}
+	if !first.stopped {
+		t.Error("the oldest effect kept playing past the limit")
+	}
+	if mixer.effects() != MaxVoices {
+		t.Errorf("%d effects playing, want %d", mixer.effects(), MaxVoices)
+	}
+	Render(mixer, NullOutput{}, 0.1)
+	if mixer.Playing() != MaxVoices+1 {
+		t.Errorf("%d voices playing, want the effects and the music", mixer.Playing())
+	}
+}
+
+func TestMixGains(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  float32
+		busGain float32
+		muted   bool
+		want    float32
+	}{
+		{"as is", 1, 1, false, 0.25},
+		{"half volume", 0.5, 1, false, 0.125},
+		{"quiet bus", 1, 0.5, false, 0.125},
+		{"muted", 1, 1, true, 0},
+		{"clipped", 8, 1, false, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mixer := NewMixer(1000)
+			mixer.SetVolume(test.volume)
+			mixer.SetBusGain(SFX, test.busGain)
+			mixer.SetMuted(test.muted)
+			mixer.Play(tone(10, 0.25), SFX, 1)
+			var output recorder
+			Render(mixer, &output, 0.01)
+			if len(output.samples) != 20 {
+				t.Fatalf("%d samples rendered, want 20", len(output.samples))
+			}
+			for _, sample := range output.samples {
+				if sample != test.want {
+					t.Fatalf("sample %v, want %v", sample, test.want)
+				}
+			}
+		})
+	}
+}
+
+// Sound effects and music mix into a WAV file the way the game
+// records with -audio-out, each bus at its own gain
+func TestWAVFileMixesBothBuses(t *testing.T) {
+	tests := []struct {
+		name  string
+		muted bool
+		// Before and after the sound effect ends
+		want [2]float32
+	}{
+		{"both buses", false, [2]float32{0.25, 0.125}},
+		{"muted", true, [2]float32{0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mixer := NewMixer(1000)
+			mixer.SetBusGain(SFX, 0.5)
+			mixer.SetBusGain(Music, 0.25)
+			mixer.SetMuted(test.muted)
+			mixer.Play(tone(50, 0.25), SFX, 1)
+			mixer.Loop(tone(10, 0.5), Music, 1)
+
+			path := filepath.Join(t.TempDir(), "mix.wav")
+			output, err := CreateWAVFile(path, 1000)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = Render(mixer, output, 0.1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = output.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			sound, err := DecodeWAV(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sound.SampleRate != 1000 || sound.Channels != 2 || len(sound.Samples) != 200 {
+				t.Fatalf("recorded %d Hz, %d channels, %d samples, want 1000 Hz, 2 channels, 200 samples",
+					sound.SampleRate, sound.Channels, len(sound.Samples))
+			}
+			for i, sample := range sound.Samples {
+				want := test.want[0]
+				if i >= 100 {
+					want = test.want[1]
+				}
+				// 16 bit samples round to a step of 1/32767
+				if diff := sample - want; diff > 1.0/32767 || diff < -1.0/32767 {
+					t.Fatalf("sample %d is %v, want %v", i, sample, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStartPipe(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell")
+	}
+	path := filepath.Join(t.TempDir(), "out.raw")
+	output, err := StartPipe("sh", "-c", "cat > "+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixer := NewMixer(1000)
+	mixer.Play(tone(100, 0.5), SFX, 1)
+	err = Render(mixer, output, 0.1)
+	if err == nil {
+		err = output.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 100*2*2 {
+		t.Errorf("player read %d bytes, want %d", len(data), 100*2*2)
+	}
+}
@@ -0,0 +1,17 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// DecodeOgg reads a whole Ogg Vorbis file
+func DecodeOgg(r io.Reader) (*Sound, error) {
+	samples, format, err := oggvorbis.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ogg: %w", err)
+	}
+	return &Sound{SampleRate: format.SampleRate, Channels: format.Channels, Samples: samples}, nil
+}
@@ -0,0 +1,141 @@
+package audio
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+)
+
+// Output consumes mixed interleaved stereo samples
+type Output interface {
+	Write(samples []float32) error
+	Close() error
+}
+
+// NullOutput drops the samples, the game runs silent
+type NullOutput struct{}
+
+func (NullOutput) Write(samples []float32) error {
+	return nil
+}
+
+func (NullOutput) Close() error {
+	return nil
+}
+
+// WAVFileOutput records the mix into a 16 bit WAV file
+type WAVFileOutput struct {
+	file       *os.File
+	writer     *bufio.Writer
+	sampleRate int
+	dataSize   uint32
+}
+
+func CreateWAVFile(path string, sampleRate int) (*WAVFileOutput, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	output := WAVFileOutput{file: file, writer: bufio.NewWriter(file), sampleRate: sampleRate}
+	// The sizes are filled in by Close
+	err = writeWAVHeader(output.writer, sampleRate, 2, 0)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &output, nil
+}
+
+func (output *WAVFileOutput) Write(samples []float32) error {
+	data := encodePCM16(samples)
+	output.dataSize += uint32(len(data))
+	_, err := output.writer.Write(data)
+	return err
+}
+
+func (output *WAVFileOutput) Close() error {
+	err := output.writer.Flush()
+	if err == nil {
+		_, err = output.file.Seek(0, 0)
+	}
+	if err == nil {
+		err = writeWAVHeader(output.file, output.sampleRate, 2, output.dataSize)
+	}
+	closeErr := output.file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// Render mixes seconds of audio into the output without waiting,
+// for tests and offline recording
+func Render(mixer *Mixer, output Output, seconds float64) error {
+	frames := int(seconds * float64(mixer.sampleRate))
+	buffer := make([]float32, 1024*2)
+	for frames > 0 {
+		chunk := buffer
+		if frames < len(buffer)/2 {
+			chunk = buffer[:frames*2]
+		}
+		mixer.Mix(chunk)
+		err := output.Write(chunk)
+		if err != nil {
+			return err
+		}
+		frames -= len(chunk) / 2
+	}
+	return nil
+}
+
+// Player feeds an output in real time from a goroutine
+type Player struct {
+	mixer  *Mixer
+	output Output
+	done   chan struct{}
+	wg     sync.WaitGroup
+	err    error
+}
+
+// Samples mixed per channel on each tick of the player
+const bufferFrames = 1024
+
+func NewPlayer(mixer *Mixer, output Output) *Player {
+	player := Player{mixer: mixer, output: output, done: make(chan struct{})}
+	player.wg.Add(1)
+	go player.run()
+	return &player
+}
+
+func (player *Player) run() {
+	defer player.wg.Done()
+	period := time.Duration(bufferFrames) * time.Second / time.Duration(player.mixer.sampleRate)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	buffer := make([]float32, bufferFrames*2)
+	for {
+		select {
+		case <-player.done:
+			return
+		case <-ticker.C:
+			player.mixer.Mix(buffer)
+			err := player.output.Write(buffer)
+			if err != nil {
+				player.err = err
+				return
+			}
+		}
+	}
+}
+
+// Close stops the player and closes the output
+func (player *Player) Close() error {
+	close(player.done)
+	player.wg.Wait()
+	err := player.output.Close()
+	if player.err != nil {
+		return player.err
+	}
+	return err
+}
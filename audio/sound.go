@@ -0,0 +1,58 @@
+package audio
+
+// Sound is decoded PCM, samples are interleaved and in -1..1
+type Sound struct {
+	SampleRate int
+	Channels   int
+	Samples    []float32
+}
+
+// Frames is the number of samples per channel
+func (sound *Sound) Frames() int {
+	if sound.Channels == 0 {
+		return 0
+	}
+	return len(sound.Samples) / sound.Channels
+}
+
+// Duration in seconds
+func (sound *Sound) Duration() float64 {
+	if sound.SampleRate == 0 {
+		return 0
+	}
+	return float64(sound.Frames()) / float64(sound.SampleRate)
+}
+
+// stereo converts the sound to two channels at the sample rate,
+// resampling linearly, so the mixer only adds samples up
+func (sound *Sound) stereo(sampleRate int) *Sound {
+	if sound.Channels == 2 && sound.SampleRate == sampleRate {
+		return sound
+	}
+	frames := sound.Frames()
+	if frames == 0 || sound.SampleRate == 0 {
+		return &Sound{SampleRate: sampleRate, Channels: 2}
+	}
+	outFrames := int(int64(frames) * int64(sampleRate) / int64(sound.SampleRate))
+	out := make([]float32, outFrames*2)
+	step := float64(sound.SampleRate) / float64(sampleRate)
+	for i := 0; i < outFrames; i++ {
+		position := float64(i) * step
+		frame := int(position)
+		weight := float32(position - float64(frame))
+		next := frame + 1
+		if next >= frames {
+			next = frames - 1
+		}
+		for channel := 0; channel < 2; channel++ {
+			source := channel
+			if source >= sound.Channels {
+				source = sound.Channels - 1
+			}
+			a := sound.Samples[frame*sound.Channels+source]
+			b := sound.Samples[next*sound.Channels+source]
+			out[i*2+channel] = a + (b-a)*weight
+		}
+	}
+	return &Sound{SampleRate: sampleRate, Channels: 2, Samples: out}
+}
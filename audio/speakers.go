@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Players tried in order by OpenSpeakers, all of them read 16 bit
+// little endian stereo samples from the standard input. The rate
+// placeholder is replaced by the sample rate.
+var speakerCommands = [][]string{
+	{"pacat", "--raw", "--format=s16le", "--channels=2", "--rate=" + ratePlaceholder},
+	{"pw-cat", "--playback", "--format=s16", "--channels=2", "--rate=" + ratePlaceholder, "-"},
+	{"aplay", "-q", "-t", "raw", "-f", "S16_LE", "-c", "2", "-r", ratePlaceholder},
+	{"play", "-q", "-t", "raw", "-e", "signed", "-b", "16", "-c", "2", "-r", ratePlaceholder, "-"},
+	{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet", "-f", "s16le", "-ac", "2", "-ar", ratePlaceholder, "-i", "-"},
+}
+
+const ratePlaceholder = "{rate}"
+
+// ErrNoSpeakers is returned by OpenSpeakers when none of the players is installed
+var ErrNoSpeakers = errors.New("audio: no sound player found, install pulseaudio-utils, alsa-utils, sox or ffmpeg")
+
+// PipeOutput plays the mix by piping it into a sound player
+type PipeOutput struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+}
+
+// OpenSpeakers starts the first sound player found on the path
+func OpenSpeakers(sampleRate int) (*PipeOutput, error) {
+	rate := strconv.Itoa(sampleRate)
+	for _, command := range speakerCommands {
+		path, err := exec.LookPath(command[0])
+		if err != nil {
+			continue
+		}
+		args := make([]string, len(command)-1)
+		for i, arg := range command[1:] {
+			args[i] = strings.ReplaceAll(arg, ratePlaceholder, rate)
+		}
+		return StartPipe(path, args...)
+	}
+	return nil, ErrNoSpeakers
+}
+
+// StartPipe runs the command and writes the samples to its standard input
+func StartPipe(name string, args ...string) (*PipeOutput, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	return &PipeOutput{cmd: cmd, stdin: stdin}, nil
+}
+
+func (output *PipeOutput) Write(samples []float32) error {
+	_, err := output.stdin.Write(encodePCM16(samples))
+	return err
+}
+
+// Close ends the input and waits for the player to finish
+func (output *PipeOutput) Close() error {
+	err := output.stdin.Close()
+	waitErr := output.cmd.Wait()
+	if err != nil {
+		return err
+	}
+	return waitErr
+}
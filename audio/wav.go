@@ -0,0 +1,158 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	wavFormatPCM   = 1
+	wavFormatFloat = 3
+	// WAVE_FORMAT_EXTENSIBLE keeps the real format in the sub format
+	wavFormatExtensible = 0xFFFE
+)
+
+// DecodeWAV reads 8, 16, 24 or 32 bit integer and 32 bit float WAV files
+func DecodeWAV(r io.Reader) (*Sound, error) {
+	var header [12]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		return nil, fmt.Errorf("wav: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("wav: not a RIFF WAVE file")
+	}
+
+	var format, channels, bits uint16
+	var sampleRate uint32
+	haveFormat := false
+	for {
+		var chunk [8]byte
+		_, err = io.ReadFull(r, chunk[:])
+		if err != nil {
+			return nil, fmt.Errorf("wav: no data chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, errors.New("wav: short fmt chunk")
+			}
+			data := make([]byte, size)
+			_, err = io.ReadFull(r, data)
+			if err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+			format = binary.LittleEndian.Uint16(data[0:2])
+			channels = binary.LittleEndian.Uint16(data[2:4])
+			sampleRate = binary.LittleEndian.Uint32(data[4:8])
+			bits = binary.LittleEndian.Uint16(data[14:16])
+			if format == wavFormatExtensible && size >= 26 {
+				format = binary.LittleEndian.Uint16(data[24:26])
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("wav: data chunk before fmt chunk")
+			}
+			if channels == 0 || sampleRate == 0 {
+				return nil, errors.New("wav: bad fmt chunk")
+			}
+			data := make([]byte, size)
+			n, err := io.ReadFull(r, data)
+			// Some writers leave the size unset, keep what is there
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+			samples, err := decodeSamples(data[:n], format, bits)
+			if err != nil {
+				return nil, err
+			}
+			return &Sound{SampleRate: int(sampleRate), Channels: int(channels), Samples: samples}, nil
+		default:
+			// Chunks are padded to an even size
+			_, err = io.CopyN(io.Discard, r, int64(size+size%2))
+			if err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+		}
+	}
+}
+
+func decodeSamples(data []byte, format, bits uint16) ([]float32, error) {
+	bytesPerSample := int(bits) / 8
+	if bytesPerSample == 0 {
+		return nil, fmt.Errorf("wav: %d bit samples", bits)
+	}
+	samples := make([]float32, len(data)/bytesPerSample)
+	for i := range samples {
+		b := data[i*bytesPerSample:]
+		switch {
+		case format == wavFormatFloat && bits == 32:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case format != wavFormatPCM:
+			return nil, fmt.Errorf("wav: unsupported format %d", format)
+		case bits == 8:
+			// 8 bit samples are unsigned
+			samples[i] = (float32(b[0]) - 128) / 128
+		case bits == 16:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		case bits == 24:
+			value := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			samples[i] = float32(value) / (1 << 23)
+		case bits == 32:
+			samples[i] = float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+		default:
+			return nil, fmt.Errorf("wav: %d bit samples", bits)
+		}
+	}
+	return samples, nil
+}
+
+// writeWAVHeader writes a 16 bit PCM header for dataSize bytes of samples
+func writeWAVHeader(w io.Writer, sampleRate, channels int, dataSize uint32) error {
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], 36+dataSize)
+	copy(header[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], wavFormatPCM)
+	binary.LittleEndian.PutUint16(header[22:24], uint16(channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(sampleRate*channels*2))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(channels*2))
+	binary.LittleEndian.PutUint16(header[34:36], 16)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], dataSize)
+	_, err := w.Write(header)
+	return err
+}
+
+// encodePCM16 converts samples to 16 bit little endian, clipping them
+func encodePCM16(samples []float32) []byte {
+	data := make([]byte, len(samples)*2)
+	for i, sample := range samples {
+		if sample > 1 {
+			sample = 1
+		} else if sample < -1 {
+			sample = -1
+		}
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(int16(sample*math.MaxInt16)))
+	}
+	return data
+}
+
+// EncodeWAV writes the sound as a 16 bit PCM WAV file
+func EncodeWAV(w io.Writer, sound *Sound) error {
+	data := encodePCM16(sound.Samples)
+	err := writeWAVHeader(w, sound.SampleRate, sound.Channels, uint32(len(data)))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,38 @@
+// Command levelgen prints generated wall layouts
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"snakegame/levelgen"
+)
+
+func main() {
+	width := flag.Int("width", 10, "board width in cells")
+	height := flag.Int("height", 10, "board height in cells")
+	difficulty := flag.Float64("difficulty", 0.5, "wall amount from 0 to 1")
+	snakeLength := flag.Int("snake", 3, "snake length at the start")
+	quota := flag.Int("quota", 15, "food the level asks for")
+	seed := flag.Int64("seed", 1, "seed of the first layout")
+	count := flag.Int("count", 1, "layouts to print, with consecutive seeds")
+	flag.Parse()
+
+	for i := 0; i < *count; i++ {
+		layout, err := levelgen.Generate(levelgen.Options{
+			Width:       *width,
+			Height:      *height,
+			Difficulty:  *difficulty,
+			SnakeLength: *snakeLength,
+			FoodQuota:   *quota,
+			Seed:        *seed + int64(i),
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "levelgen:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("seed %d, %d walls, %d free cells\n", *seed+int64(i), len(layout.Walls), layout.FreeCells())
+		fmt.Print(layout)
+		fmt.Println()
+	}
+}
@@ -0,0 +1,57 @@
+// Command puzzles solves the shipped puzzles, it fails when one of them
+// can't be solved within its move limit
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"snakegame/assets"
+	"snakegame/helpers"
+	"snakegame/puzzle"
+)
+
+func main() {
+	limit := flag.Int("limit", 200, "most moves searched for puzzles without a move limit")
+	showPath := flag.Bool("path", false, "print the moves of every solution")
+	flag.Parse()
+
+	f, err := assets.Open("puzzles/puzzles.json")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	puzzles, err := puzzle.Load(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for i, p := range puzzles {
+		board, err := p.Board(helpers.FieldSize)
+		if err != nil {
+			fmt.Printf("%d %s: %v\n", i+1, p.Name, err)
+			failed = true
+			continue
+		}
+		searched := *limit
+		if p.MaxMoves > 0 {
+			searched = p.MaxMoves
+		}
+		solution, ok := puzzle.Solve(board, searched)
+		if !ok {
+			fmt.Printf("%d %s: no solution within %d moves\n", i+1, p.Name, searched)
+			failed = true
+			continue
+		}
+		fmt.Printf("%d %s: %d moves, %d allowed\n", i+1, p.Name, solution.Moves, p.MaxMoves)
+		if *showPath {
+			fmt.Println("  ", solution.Path)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,51 @@
+package collision
+
+import (
+	"snakegame/events"
+	"snakegame/hazard"
+	"snakegame/helpers"
+	"snakegame/rival"
+	"snakegame/snakemodule"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Board is everything the head can run into on a step
+type Board struct {
+	Snake   *snakemodule.Snake
+	Hazards *hazard.State
+	Rivals  []*rival.Rival
+	// The head passes through the body
+	Ghost bool
+	// Game time of the step, spikes come and go with it
+	Now float64
+}
+
+// At decides what the head runs into on the cell,
+// on whole cells so it doesn't depend on the speed
+func (board Board) At(cell mgl32.Vec2) (events.DeathCause, bool) {
+	switch {
+	case !helpers.InField(int(cell.X()), int(cell.Y())) || board.Hazards.WallAt(cell):
+		return events.HitWall, true
+	case board.Hazards.SpikeAt(cell, board.Now):
+		return events.HitSpike, true
+	case !board.Ghost && board.Snake.Blocked(cell):
+		return events.HitSelf, true
+	}
+	for _, r := range board.Rivals {
+		if r.Snake.Occupies(cell) {
+			return events.HitRival, true
+		}
+	}
+	return 0, false
+}
+
+// Step is At for a step onto the cell, through a portal
+// it is the exit the head comes out on that counts too
+func (board Board) Step(cell mgl32.Vec2) (events.DeathCause, bool) {
+	cause, hit := board.At(cell)
+	if exit, _, ok := board.Hazards.Portal(cell); ok && !hit {
+		return board.At(exit)
+	}
+	return cause, hit
+}
@@ -0,0 +1,90 @@
+package collision
+
+import (
+	"snakegame/events"
+	"snakegame/hazard"
+	"snakegame/helpers"
+	"snakegame/rival"
+	"snakegame/snakemodule"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func cells(coords ...[2]float32) []mgl32.Vec2 {
+	list := make([]mgl32.Vec2, len(coords))
+	for i, c := range coords {
+		list[i] = mgl32.Vec2{c[0], c[1]}
+	}
+	return list
+}
+
+func testBoard(ghost bool) Board {
+	hazards := hazard.NewState(1)
+	hazards.Reset(hazard.Level{
+		Walls: cells([2]float32{3, 0}, [2]float32{9, 9}),
+		Spikes: []hazard.Spike{
+			{Position: mgl32.Vec2{5, 0}},
+			{Position: mgl32.Vec2{6, 0}, Period: 4, On: 1, Offset: 2},
+		},
+		Portals: []hazard.Portal{
+			{A: mgl32.Vec2{4, 6}, B: mgl32.Vec2{1, 4}},
+			{A: mgl32.Vec2{6, 6}, B: mgl32.Vec2{8, 2}},
+			{A: mgl32.Vec2{6, 8}, B: mgl32.Vec2{9, 9}},
+			{A: mgl32.Vec2{2, 8}, B: mgl32.Vec2{0, 4}},
+		},
+	}, 0)
+	return Board{
+		Snake:   snakemodule.NewSnake(cells([2]float32{0, 4}, [2]float32{1, 4}, [2]float32{2, 4}, [2]float32{3, 4})),
+		Hazards: hazards,
+		Rivals:  []*rival.Rival{{Snake: snakemodule.NewSnake(cells([2]float32{8, 2}, [2]float32{8, 3}))}},
+		Ghost:   ghost,
+	}
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		cell  mgl32.Vec2
+		ghost bool
+		want  events.DeathCause
+		hit   bool
+	}{
+		{name: "open cell", cell: mgl32.Vec2{3, 1}},
+		{name: "wall", cell: mgl32.Vec2{3, 0}, want: events.HitWall, hit: true},
+		{name: "corner wall", cell: mgl32.Vec2{9, 9}, want: events.HitWall, hit: true},
+		{name: "left edge", cell: mgl32.Vec2{-1, 0}, want: events.HitWall, hit: true},
+		{name: "bottom edge", cell: mgl32.Vec2{0, -1}, want: events.HitWall, hit: true},
+		{name: "right edge", cell: mgl32.Vec2{helpers.FieldSize, 4}, want: events.HitWall, hit: true},
+		{name: "top edge", cell: mgl32.Vec2{4, helpers.FieldSize}, want: events.HitWall, hit: true},
+		{name: "spike out", cell: mgl32.Vec2{5, 0}, want: events.HitSpike, hit: true},
+		{name: "spike in", cell: mgl32.Vec2{6, 0}},
+		{name: "own body", cell: mgl32.Vec2{1, 4}, want: events.HitSelf, hit: true},
+		{name: "own tail moving away", cell: mgl32.Vec2{0, 4}},
+		{name: "ghost through the body", cell: mgl32.Vec2{1, 4}, ghost: true},
+		{name: "ghost on a wall", cell: mgl32.Vec2{3, 0}, ghost: true, want: events.HitWall, hit: true},
+		{name: "rival", cell: mgl32.Vec2{8, 3}, want: events.HitRival, hit: true},
+		{name: "ghost on a rival", cell: mgl32.Vec2{8, 3}, ghost: true, want: events.HitRival, hit: true},
+		{name: "portal exit on the body", cell: mgl32.Vec2{4, 6}, want: events.HitSelf, hit: true},
+		{name: "ghost through a portal onto the body", cell: mgl32.Vec2{4, 6}, ghost: true},
+		{name: "portal exit on a rival", cell: mgl32.Vec2{6, 6}, want: events.HitRival, hit: true},
+		{name: "portal exit on a wall", cell: mgl32.Vec2{6, 8}, want: events.HitWall, hit: true},
+		{name: "portal exit on the tail moving away", cell: mgl32.Vec2{2, 8}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cause, hit := testBoard(test.ghost).Step(test.cell)
+			if hit != test.hit || hit && cause != test.want {
+				t.Errorf("step onto %v = %v, %v, want %v, %v", test.cell, cause, hit, test.want, test.hit)
+			}
+		})
+	}
+}
+
+// Only the step looks through a portal, the head on the cell itself
+// hits nothing there
+func TestAtIgnoresPortals(t *testing.T) {
+	if cause, hit := testBoard(false).At(mgl32.Vec2{4, 6}); hit {
+		t.Errorf("portal cell hit %v", cause)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"snakegame/daily"
+	"snakegame/graphics"
+	"snakegame/rival"
+	"snakegame/snakemodule"
+)
+
+var verifyCode = flag.String("verify", "", "check a daily challenge code by replaying it and exit")
+
+var dailyChallenge = daily.Today()
+var dailyRecords daily.Records
+
+// Moves of the current daily run, for its verification code
+var dailyMoves []daily.Move
+
+// verifyDaily prints whether the code holds up, the result is the exit status
+func verifyDaily(code string) int {
+	result, err := daily.Verify(code)
+	if err != nil {
+		fmt.Println("invalid:", err)
+		return 1
+	}
+	fmt.Printf("valid: score %d in %d steps\n", result.Score, result.Steps)
+	return 0
+}
+
+// beginDaily counts the attempt, the day may have changed since the start
+func beginDaily() {
+	dailyChallenge = daily.Today()
+	record := dailyRecords[dailyChallenge.Date]
+	record.Attempts++
+	dailyRecords[dailyChallenge.Date] = record
+	saveDailyRecords()
+	timeWindow = dailyChallenge.TimeWindow
+	dailyMoves = nil
+}
+
+// resetDailyBoard sets up the walls of the day and the food
+// sequence from its seed, without power-ups or rivals
+func resetDailyBoard() {
+	now := clock()
+	rivals.Reset(rival.Difficulty{}, nil)
+	powerUps.Clear(now, 0)
+	hazards.Reset(dailyChallenge.Level, now)
+	foods = snakemodule.NewFoodSet(dailyChallenge.Seed)
+	fillFood()
+}
+
+func recordDailyMove() {
+	dailyMoves = append(dailyMoves, daily.MoveOf(directionVector()))
+}
+
+// finishDaily keeps the code of the best run of the day,
+// it returns the best score before the run and 1 for a new best
+func finishDaily(score int) (best, rank int) {
+	record := dailyRecords[dailyChallenge.Date]
+	best = record.Best
+	code := daily.Encode(dailyChallenge.Date, score, dailyMoves)
+	if record.Code == "" || score > record.Best {
+		record.Best = score
+		record.Code = code
+		rank = 1
+	}
+	dailyRecords[dailyChallenge.Date] = record
+	saveDailyRecords()
+	log.Printf("daily code: %s", code)
+	return best, rank
+}
+
+// copyDailyCode puts the best code of the day on the clipboard
+func copyDailyCode() {
+	code := dailyRecords[dailyChallenge.Date].Code
+	if code == "" {
+		return
+	}
+	graphics.SetClipboard(code)
+	log.Printf("daily code copied")
+}
+
+func saveDailyRecords() {
+	err := dailyRecords.Save()
+	if err != nil {
+		log.Printf("warning: daily records not saved: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package daily
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"snakegame/hazard"
+	"snakegame/helpers"
+	"snakegame/levelgen"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Date format of the challenges, days go by UTC so everyone shares them
+const DateLayout = "2006-01-02"
+
+// Snake length at the start, the same as a level start
+const SnakeLength = 3
+
+// Challenge is the board everyone plays on one day
+type Challenge struct {
+	Date string
+	// Seeds the walls, the speed and the food sequence
+	Seed       int64
+	Level      hazard.Level
+	TimeWindow float32
+}
+
+func Today() Challenge {
+	return ForDate(time.Now().UTC().Format(DateLayout))
+}
+
+// ForDate builds the challenge of the day, the same one on every machine
+func ForDate(date string) Challenge {
+	seed := Seed(date)
+	r := rand.New(rand.NewSource(seed))
+	return Challenge{
+		Date:       date,
+		Seed:       seed,
+		Level:      hazard.Level{Walls: walls(r)},
+		TimeWindow: 0.3 + 0.05*float32(r.Intn(4)),
+	}
+}
+
+func Seed(date string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("snakegame daily " + date))
+	return int64(hash.Sum64())
+}
+
+// walls come from the level generator, seeded by the day
+func walls(r *rand.Rand) []mgl32.Vec2 {
+	layout, _ := levelgen.Generate(levelgen.Options{
+		Width:       helpers.FieldSize,
+		Height:      helpers.FieldSize,
+		Difficulty:  0.2 + 0.1*float64(r.Intn(4)),
+		SnakeLength: SnakeLength,
+		FoodQuota:   30,
+		Seed:        r.Int63(),
+	})
+	return layout.Walls
+}
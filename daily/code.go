@@ -0,0 +1,99 @@
+package daily
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"snakegame/helpers"
+	"strconv"
+	"strings"
+)
+
+// Version prefix of the verification codes
+const codePrefix = "daily1"
+
+// Encode writes a run as a verification code, the moves are run-length
+// encoded and a checksum catches codes mangled in copying
+func Encode(date string, score int, moves []Move) string {
+	var body strings.Builder
+	for i := 0; i < len(moves); {
+		run := 1
+		for i+run < len(moves) && moves[i+run] == moves[i] {
+			run++
+		}
+		body.WriteByte(byte(moves[i]))
+		if run > 1 {
+			body.WriteString(strconv.Itoa(run))
+		}
+		i += run
+	}
+	text := fmt.Sprintf("%s:%s:%d:%s", codePrefix, date, score, body.String())
+	return text + ":" + checksum(text)
+}
+
+func checksum(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Decode reads a verification code back into the run
+func Decode(code string) (date string, score int, moves []Move, err error) {
+	parts := strings.Split(strings.TrimSpace(code), ":")
+	if len(parts) != 5 || parts[0] != codePrefix {
+		return "", 0, nil, errors.New("not a daily code")
+	}
+	if checksum(strings.Join(parts[:4], ":")) != parts[4] {
+		return "", 0, nil, errors.New("checksum mismatch, the code is damaged")
+	}
+	date = parts[1]
+	score, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("bad score: %w", err)
+	}
+	body := parts[3]
+	for i := 0; i < len(body); {
+		move := Move(body[i])
+		if move != Up && move != Down && move != Left && move != Right {
+			return "", 0, nil, fmt.Errorf("bad move %q", body[i])
+		}
+		i++
+		end := i
+		for end < len(body) && body[end] >= '0' && body[end] <= '9' {
+			end++
+		}
+		run := 1
+		if end > i {
+			run, err = strconv.Atoi(body[i:end])
+			if err != nil {
+				return "", 0, nil, fmt.Errorf("bad run of moves: %w", err)
+			}
+			// A straight run any longer would leave the board
+			if run < 1 || run > helpers.FieldSize {
+				return "", 0, nil, fmt.Errorf("bad run of %d moves", run)
+			}
+		}
+		for ; run > 0; run-- {
+			moves = append(moves, move)
+		}
+		i = end
+	}
+	return date, score, moves, nil
+}
+
+// Verify replays the code on the board of its day. The snake
+// mustn't crash before the last move and has to reach the score.
+func Verify(code string) (Result, error) {
+	date, score, moves, err := Decode(code)
+	if err != nil {
+		return Result{}, err
+	}
+	result := ForDate(date).Simulate(moves)
+	if result.Steps < len(moves) {
+		return result, fmt.Errorf("the snake crashes on step %d of %d", result.Steps, len(moves))
+	}
+	if result.Score != score {
+		return result, fmt.Errorf("the moves score %d, the code claims %d", result.Score, score)
+	}
+	return result, nil
+}
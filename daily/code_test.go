@@ -0,0 +1,99 @@
+package daily
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func moves(text string) []Move {
+	list := make([]Move, len(text))
+	for i := range text {
+		list[i] = Move(text[i])
+	}
+	return list
+}
+
+func TestCodeRoundTrip(t *testing.T) {
+	tests := []string{
+		"",
+		"R",
+		"RRRUUL",
+		"UDLR",
+		strings.Repeat("R", 10) + strings.Repeat("U", 9) + "LD",
+	}
+	for _, test := range tests {
+		code := Encode("2024-03-01", 7, moves(test))
+		date, score, decoded, err := Decode(code)
+		if err != nil {
+			t.Fatalf("%q: %v", code, err)
+		}
+		if date != "2024-03-01" || score != 7 || string(moveText(decoded)) != test {
+			t.Errorf("%q decoded to %s, %d, %q", code, date, score, moveText(decoded))
+		}
+	}
+}
+
+func moveText(list []Move) []byte {
+	text := make([]byte, len(list))
+	for i, move := range list {
+		text[i] = byte(move)
+	}
+	return text
+}
+
+// signed builds a code with a good checksum around the fields
+func signed(date, score, body string) string {
+	text := fmt.Sprintf("%s:%s:%s:%s", codePrefix, date, score, body)
+	return text + ":" + checksum(text)
+}
+
+func TestDecodeRejectsMalformedCodes(t *testing.T) {
+	good := Encode("2024-03-01", 3, moves("RRUL"))
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"empty", ""},
+		{"missing parts", "daily1:2024-03-01:3"},
+		{"other version", strings.Replace(good, codePrefix, "daily9", 1)},
+		{"damaged", strings.Replace(good, "R2", "R3", 1)},
+		{"bad checksum", good[:len(good)-1] + "x"},
+		{"bad score", signed("2024-03-01", "lots", "R2")},
+		{"bad move", signed("2024-03-01", "3", "R2X")},
+		{"digits first", signed("2024-03-01", "3", "2R")},
+		{"run of none", signed("2024-03-01", "3", "R0")},
+		{"run off the board", signed("2024-03-01", "3", "R11")},
+		{"run too long to read", signed("2024-03-01", "3", "R99999999999999999999999")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, decoded, err := Decode(test.code)
+			if err == nil {
+				t.Errorf("%q decoded to %q", test.code, moveText(decoded))
+			}
+		})
+	}
+}
+
+func TestVerifyOwnRun(t *testing.T) {
+	challenge := ForDate("2024-03-01")
+	// Go round in a small loop, turning before any wall
+	var run []Move
+	for i := 0; i < 3; i++ {
+		run = append(run, moves("RUUL")...)
+	}
+	result := challenge.Simulate(run)
+	code := Encode("2024-03-01", result.Score, run[:result.Steps])
+	verified, err := Verify(code)
+	if err != nil {
+		t.Fatalf("%q: %v", code, err)
+	}
+	if verified.Score != result.Score {
+		t.Errorf("verified score %d, played %d", verified.Score, result.Score)
+	}
+	_, err = Verify(Encode("2024-03-01", result.Score+1, run[:result.Steps]))
+	if err == nil {
+		t.Error("a code claiming a higher score was verified")
+	}
+}
@@ -0,0 +1,59 @@
+package daily
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Record is what was played on one day
+type Record struct {
+	Attempts int    `json:"attempts"`
+	Best     int    `json:"best"`
+	Code     string `json:"code"`
+}
+
+// Records are kept by date
+type Records map[string]Record
+
+// Path of the records file in the user config dir
+func Path() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "daily.json"
+	}
+	return filepath.Join(dir, "snakegame", "daily.json")
+}
+
+// LoadRecords returns the saved records, or none
+func LoadRecords() Records {
+	records := Records{}
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("warning: %v, starting new daily records", err)
+		}
+		return records
+	}
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		log.Printf("warning: daily records %s: %v, starting new daily records", Path(), err)
+		return Records{}
+	}
+	return records
+}
+
+func (records Records) Save() error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(Path()), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(), data, 0644)
+}
@@ -0,0 +1,103 @@
+package daily
+
+import (
+	"snakegame/helpers"
+	"snakegame/snakemodule"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Move is the direction of the snake on one step
+type Move byte
+
+const (
+	Up    Move = 'U'
+	Down  Move = 'D'
+	Left  Move = 'L'
+	Right Move = 'R'
+)
+
+// MoveOf returns the move of a unit direction
+func MoveOf(direction mgl32.Vec2) Move {
+	switch {
+	case direction.X() > 0:
+		return Right
+	case direction.X() < 0:
+		return Left
+	case direction.Y() > 0:
+		return Up
+	}
+	return Down
+}
+
+func (move Move) Vector() mgl32.Vec2 {
+	switch move {
+	case Right:
+		return mgl32.Vec2{1, 0}
+	case Left:
+		return mgl32.Vec2{-1, 0}
+	case Up:
+		return mgl32.Vec2{0, 1}
+	}
+	return mgl32.Vec2{0, -1}
+}
+
+// Result of playing moves on a challenge
+type Result struct {
+	Score int
+	// Steps played, the last one is the crash of a dead snake
+	Steps int
+	Dead  bool
+}
+
+// Weights of the food on the daily board, plain food only
+var Weights = snakemodule.FoodWeights{snakemodule.NormalFood: 1}
+
+// Simulate plays the moves one step each, the way the game loop
+// does on the daily board: food is placed from the challenge seed
+// and the snake dies running into the edge, a wall or itself.
+func (challenge Challenge) Simulate(moves []Move) Result {
+	snake := snakemodule.InitSnake(SnakeLength)
+	foods := snakemodule.NewFoodSet(challenge.Seed)
+	walls := make(map[int]bool)
+	for _, wall := range challenge.Level.Walls {
+		walls[helpers.CoordsToIndex(int(wall.X()), int(wall.Y()))] = true
+	}
+	fill := func() {
+		busy := make(map[int]bool)
+		for _, index := range snake.Indices() {
+			busy[index] = true
+		}
+		for _, index := range foods.Indices() {
+			busy[index] = true
+		}
+		var free []int
+		for i := 0; i < helpers.FieldSize*helpers.FieldSize; i++ {
+			if !busy[i] && !walls[i] {
+				free = append(free, i)
+			}
+		}
+		foods.Fill(1, Weights, free, 0)
+	}
+	fill()
+
+	result := Result{}
+	for _, move := range moves {
+		result.Steps++
+		head := snake.GetHead()
+		target := head.GetCoords().Add(move.Vector())
+		x, y := int(target.X()), int(target.Y())
+		if !helpers.InField(x, y) || walls[helpers.CoordsToIndex(x, y)] || snake.Blocked(target) {
+			result.Dead = true
+			return result
+		}
+		snake.SetFront(target)
+		food, eaten := snake.Eat(foods)
+		snake.Move(target)
+		if eaten {
+			result.Score += food.Kind.Type().Points
+			fill()
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log"
+	"snakegame/daily"
+	"snakegame/difficulty"
+	"snakegame/mode"
+)
+
+// Preset chosen on the start screen, saved in the settings
+var gameDifficulty difficulty.Preset
+
+// Speed tuning from the deaths and reactions of the session,
+// used while the adaptive setting is on
+var adaptive = difficulty.NewAdaptive()
+
+// Time the food the player reacts to showed up, 0 after the first turn
+var foodShownAt float64
+var levelStartedAt float64
+
+func initDifficulty() {
+	gameDifficulty = difficulty.Parse(currentSettings.Difficulty)
+}
+
+// selectDifficulty moves the start screen selection and saves it
+func selectDifficulty(step int) {
+	gameDifficulty = gameDifficulty.Next(step)
+	currentSettings.Difficulty = gameDifficulty.String()
+	saveDifficulty()
+}
+
+func toggleAdaptive() {
+	currentSettings.Adaptive = !currentSettings.Adaptive
+	saveDifficulty()
+}
+
+func saveDifficulty() {
+	err := currentSettings.Save()
+	if err != nil {
+		log.Printf("warning: settings not saved: %v", err)
+	}
+}
+
+// currentDifficulty is the preset in play, modes with fixed rules
+// always play the normal one
+func currentDifficulty() difficulty.Settings {
+	if gameMode.Rules().FixedDifficulty {
+		return difficulty.Normal.Settings()
+	}
+	return gameDifficulty.Settings()
+}
+
+// difficultyName is kept with the leaderboard entries
+func difficultyName() string {
+	if gameMode.Rules().FixedDifficulty {
+		return ""
+	}
+	name := gameDifficulty.String()
+	if currentSettings.Adaptive {
+		name += " adaptive"
+	}
+	return name
+}
+
+// adapting reports whether the speed follows the player
+func adapting() bool {
+	return currentSettings.Adaptive && !gameMode.Rules().FixedDifficulty
+}
+
+// tuneTimeWindow applies the adaptive scale to a step time
+func tuneTimeWindow(window float32) float32 {
+	if adapting() {
+		return adaptive.Window(window)
+	}
+	return difficulty.Clamp(window)
+}
+
+// startLength is the snake length levels start with
+func startLength() int {
+	if gameMode == mode.Daily {
+		return daily.SnakeLength
+	}
+	return currentDifficulty().SnakeLength
+}
@@ -0,0 +1,68 @@
+package difficulty
+
+import "math"
+
+// Bounds of the adaptive scale, the game gets at most
+// a third faster or half again as slow as the preset
+const (
+	MinScale = 0.75
+	MaxScale = 1.5
+)
+
+// Reactions averaged before the speed is tuned
+const reactionCount = 5
+
+// Adaptive tunes the step time to how the player is doing,
+// quick deaths and slow reactions slow the game down while
+// finished levels and quick reactions speed it up
+type Adaptive struct {
+	// Multiplies the step time, above 1 the game is slower
+	Scale     float64
+	reactions []float64
+}
+
+func NewAdaptive() *Adaptive {
+	return &Adaptive{Scale: 1}
+}
+
+// Died takes the seconds the snake lived on the level
+func (adaptive *Adaptive) Died(seconds float64) {
+	if seconds < 30 {
+		adaptive.scale(1.15)
+	} else {
+		adaptive.scale(1.05)
+	}
+}
+
+func (adaptive *Adaptive) LevelCompleted() {
+	adaptive.scale(0.95)
+}
+
+// Reacted takes the steps it took the player to turn after new food
+// showed up, the speed is tuned once enough of them are in
+func (adaptive *Adaptive) Reacted(steps float64) {
+	adaptive.reactions = append(adaptive.reactions, steps)
+	if len(adaptive.reactions) < reactionCount {
+		return
+	}
+	total := 0.0
+	for _, reaction := range adaptive.reactions {
+		total += reaction
+	}
+	adaptive.reactions = adaptive.reactions[:0]
+	switch mean := total / reactionCount; {
+	case mean < 2:
+		adaptive.scale(0.97)
+	case mean > 5:
+		adaptive.scale(1.03)
+	}
+}
+
+func (adaptive *Adaptive) scale(factor float64) {
+	adaptive.Scale = math.Min(MaxScale, math.Max(MinScale, adaptive.Scale*factor))
+}
+
+// Window scales the step time, keeping it within MinWindow and MaxWindow
+func (adaptive *Adaptive) Window(window float32) float32 {
+	return Clamp(window * float32(adaptive.Scale))
+}
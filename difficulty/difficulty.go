@@ -0,0 +1,86 @@
+package difficulty
+
+import "math"
+
+type Preset int
+
+const (
+	Easy Preset = iota
+	Normal
+	Hard
+	Insane
+	Count
+)
+
+// Step times are kept within these bounds whatever the preset
+// or the adaptive scale, below the lower one the game can't be played
+const (
+	MinWindow = 0.05
+	MaxWindow = 0.8
+)
+
+// Settings say how fast a preset plays and how much it asks for
+type Settings struct {
+	// Saved in the settings and the leaderboard
+	Name string
+	// Shown on the start screen
+	Title string
+	// Step time in seconds on the first level, taken off by
+	// Step on every level after it, but never below Floor
+	Start float32
+	Step  float32
+	Floor float32
+	// Food to eat on the first level and more on every level after it
+	FoodQuota    int
+	FoodPerLevel int
+	SnakeLength  int
+}
+
+var presets = [Count]Settings{
+	Easy:   {Name: "easy", Title: "EASY", Start: 0.6, Step: 0.08, Floor: 0.2, FoodQuota: 10, FoodPerLevel: 3, SnakeLength: 3},
+	Normal: {Name: "normal", Title: "NORMAL", Start: 0.5, Step: 0.1, Floor: 0.1, FoodQuota: 15, FoodPerLevel: 5, SnakeLength: 3},
+	Hard:   {Name: "hard", Title: "HARD", Start: 0.4, Step: 0.08, Floor: 0.08, FoodQuota: 20, FoodPerLevel: 6, SnakeLength: 4},
+	Insane: {Name: "insane", Title: "INSANE", Start: 0.3, Step: 0.06, Floor: 0.06, FoodQuota: 25, FoodPerLevel: 8, SnakeLength: 5},
+}
+
+func (preset Preset) Settings() Settings {
+	return presets[preset]
+}
+
+func (preset Preset) String() string {
+	return presets[preset].Name
+}
+
+// Parse returns the preset named name, or the normal one
+func Parse(name string) Preset {
+	for preset := Easy; preset < Count; preset++ {
+		if preset.String() == name {
+			return preset
+		}
+	}
+	return Normal
+}
+
+// Next cycles through the presets, step is 1 or -1
+func (preset Preset) Next(step int) Preset {
+	return Preset((int(preset) + step + int(Count)) % int(Count))
+}
+
+// TimeWindow is the step time on the level
+func (settings Settings) TimeWindow(level int) float32 {
+	window := settings.Start - settings.Step*float32(level)
+	if window < settings.Floor {
+		window = settings.Floor
+	}
+	return Clamp(window)
+}
+
+// FoodLimit is the food to eat to finish the level
+func (settings Settings) FoodLimit(level int) int {
+	return settings.FoodQuota + settings.FoodPerLevel*level
+}
+
+// Clamp keeps a step time within MinWindow and MaxWindow
+func Clamp(window float32) float32 {
+	return float32(math.Min(MaxWindow, math.Max(MinWindow, float64(window))))
+}
@@ -0,0 +1,150 @@
+package events
+
+// Bus delivers events synchronously to subscribers,
+// in the order they subscribed
+type Bus struct {
+	gameStarted      []func(GameStarted)
+	foodEaten        []func(FoodEaten)
+	snakeMoved       []func(SnakeMoved)
+	levelCompleted   []func(LevelCompleted)
+	snakeDied        []func(SnakeDied)
+	directionChanged []func(DirectionChanged)
+	paused           []func(Paused)
+	powerUpCollected []func(PowerUpCollected)
+	shieldUsed       []func(ShieldUsed)
+	poisonEaten      []func(PoisonEaten)
+	teleported       []func(Teleported)
+	rivalDied        []func(RivalDied)
+	timeUp           []func(TimeUp)
+	outOfMoves       []func(OutOfMoves)
+	all              []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (bus *Bus) OnGameStarted(handler func(GameStarted)) {
+	bus.gameStarted = append(bus.gameStarted, handler)
+}
+
+func (bus *Bus) OnFoodEaten(handler func(FoodEaten)) {
+	bus.foodEaten = append(bus.foodEaten, handler)
+}
+
+func (bus *Bus) OnSnakeMoved(handler func(SnakeMoved)) {
+	bus.snakeMoved = append(bus.snakeMoved, handler)
+}
+
+func (bus *Bus) OnLevelCompleted(handler func(LevelCompleted)) {
+	bus.levelCompleted = append(bus.levelCompleted, handler)
+}
+
+func (bus *Bus) OnSnakeDied(handler func(SnakeDied)) {
+	bus.snakeDied = append(bus.snakeDied, handler)
+}
+
+func (bus *Bus) OnDirectionChanged(handler func(DirectionChanged)) {
+	bus.directionChanged = append(bus.directionChanged, handler)
+}
+
+func (bus *Bus) OnPaused(handler func(Paused)) {
+	bus.paused = append(bus.paused, handler)
+}
+
+func (bus *Bus) OnPowerUpCollected(handler func(PowerUpCollected)) {
+	bus.powerUpCollected = append(bus.powerUpCollected, handler)
+}
+
+func (bus *Bus) OnShieldUsed(handler func(ShieldUsed)) {
+	bus.shieldUsed = append(bus.shieldUsed, handler)
+}
+
+func (bus *Bus) OnPoisonEaten(handler func(PoisonEaten)) {
+	bus.poisonEaten = append(bus.poisonEaten, handler)
+}
+
+func (bus *Bus) OnTeleported(handler func(Teleported)) {
+	bus.teleported = append(bus.teleported, handler)
+}
+
+func (bus *Bus) OnRivalDied(handler func(RivalDied)) {
+	bus.rivalDied = append(bus.rivalDied, handler)
+}
+
+func (bus *Bus) OnTimeUp(handler func(TimeUp)) {
+	bus.timeUp = append(bus.timeUp, handler)
+}
+
+func (bus *Bus) OnOutOfMoves(handler func(OutOfMoves)) {
+	bus.outOfMoves = append(bus.outOfMoves, handler)
+}
+
+// OnAny receives every event, after the typed subscribers
+func (bus *Bus) OnAny(handler func(Event)) {
+	bus.all = append(bus.all, handler)
+}
+
+func (bus *Bus) Publish(event Event) {
+	switch e := event.(type) {
+	case GameStarted:
+		for _, handler := range bus.gameStarted {
+			handler(e)
+		}
+	case FoodEaten:
+		for _, handler := range bus.foodEaten {
+			handler(e)
+		}
+	case SnakeMoved:
+		for _, handler := range bus.snakeMoved {
+			handler(e)
+		}
+	case LevelCompleted:
+		for _, handler := range bus.levelCompleted {
+			handler(e)
+		}
+	case SnakeDied:
+		for _, handler := range bus.snakeDied {
+			handler(e)
+		}
+	case DirectionChanged:
+		for _, handler := range bus.directionChanged {
+			handler(e)
+		}
+	case Paused:
+		for _, handler := range bus.paused {
+			handler(e)
+		}
+	case PowerUpCollected:
+		for _, handler := range bus.powerUpCollected {
+			handler(e)
+		}
+	case ShieldUsed:
+		for _, handler := range bus.shieldUsed {
+			handler(e)
+		}
+	case PoisonEaten:
+		for _, handler := range bus.poisonEaten {
+			handler(e)
+		}
+	case Teleported:
+		for _, handler := range bus.teleported {
+			handler(e)
+		}
+	case RivalDied:
+		for _, handler := range bus.rivalDied {
+			handler(e)
+		}
+	case TimeUp:
+		for _, handler := range bus.timeUp {
+			handler(e)
+		}
+	case OutOfMoves:
+		for _, handler := range bus.outOfMoves {
+			handler(e)
+		}
+	}
+	for _, handler := range bus.all {
+		handler(event)
+	}
+}
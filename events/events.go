@@ -0,0 +1,135 @@
+package events
+
+import (
+	"snakegame/hazard"
+	"snakegame/powerup"
+	"snakegame/snakemodule"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Event is one of the game events below
+type Event interface {
+	event()
+}
+
+// GameStarted is sent when the snake starts moving on a level
+type GameStarted struct {
+	Level int
+	Time  float64
+}
+
+type FoodEaten struct {
+	Position mgl32.Vec2
+	Kind     snakemodule.FoodKind
+	// Food points scored on the level so far, this one included
+	Count int
+	Time  float64
+}
+
+// SnakeMoved is sent after every step of the snake
+type SnakeMoved struct {
+	Head mgl32.Vec2
+	Time float64
+}
+
+type LevelCompleted struct {
+	Level     int
+	NextLevel int
+	Time      float64
+}
+
+type DeathCause int
+
+const (
+	HitWall DeathCause = iota
+	HitSelf
+	HitSpike
+	HitRival
+)
+
+func (cause DeathCause) String() string {
+	switch cause {
+	case HitWall:
+		return "wall"
+	case HitSpike:
+		return "spike"
+	case HitRival:
+		return "rival"
+	}
+	return "self"
+}
+
+type SnakeDied struct {
+	Cause    DeathCause
+	Position mgl32.Vec2
+	Level    int
+	Time     float64
+}
+
+// DirectionChanged carries the new unit direction of movement
+type DirectionChanged struct {
+	Direction mgl32.Vec2
+	Time      float64
+}
+
+type Paused struct {
+	Paused bool
+	Time   float64
+}
+
+type PowerUpCollected struct {
+	Kind     powerup.Kind
+	Position mgl32.Vec2
+	Time     float64
+}
+
+// ShieldUsed is sent when a shield saves the snake from a wall
+type ShieldUsed struct {
+	Position mgl32.Vec2
+	Time     float64
+}
+
+type PoisonEaten struct {
+	Kind     hazard.PoisonKind
+	Position mgl32.Vec2
+	Time     float64
+}
+
+// Teleported is sent when the head goes through a portal
+type Teleported struct {
+	From mgl32.Vec2
+	To   mgl32.Vec2
+	Time float64
+}
+
+// TimeUp is sent when the clock of a timed run runs out
+type TimeUp struct {
+	Time float64
+}
+
+// OutOfMoves is sent when a puzzle runs out of moves before it is solved
+type OutOfMoves struct {
+	Time float64
+}
+
+// RivalDied is sent when a computer snake crashes
+type RivalDied struct {
+	Position mgl32.Vec2
+	Time     float64
+}
+
+func (GameStarted) event()      {}
+func (FoodEaten) event()        {}
+func (SnakeMoved) event()       {}
+func (LevelCompleted) event()   {}
+func (SnakeDied) event()        {}
+func (DirectionChanged) event() {}
+func (Paused) event()           {}
+func (PowerUpCollected) event() {}
+func (ShieldUsed) event()       {}
+func (PoisonEaten) event()      {}
+func (Teleported) event()       {}
+func (RivalDied) event()        {}
+func (TimeUp) event()           {}
+func (OutOfMoves) event()       {}
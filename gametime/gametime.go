@@ -0,0 +1,41 @@
+package gametime
+
+// Clock counts the game time in seconds from a source like the window
+// timer. It stands still while the game is paused, so everything timed
+// by it, power-ups, food and hazards, waits for the game to go on.
+type Clock struct {
+	source   func() float64
+	paused   bool
+	pausedAt float64
+	// Seconds spent paused, taken off the source time
+	offset float64
+}
+
+func New(source func() float64) *Clock {
+	return &Clock{source: source}
+}
+
+// Now is the game time, the source time less the pauses
+func (clock *Clock) Now() float64 {
+	if clock.paused {
+		return clock.pausedAt - clock.offset
+	}
+	return clock.source() - clock.offset
+}
+
+// SetPaused stops or restarts the clock
+func (clock *Clock) SetPaused(paused bool) {
+	if paused == clock.paused {
+		return
+	}
+	if paused {
+		clock.pausedAt = clock.source()
+	} else {
+		clock.offset += clock.source() - clock.pausedAt
+	}
+	clock.paused = paused
+}
+
+func (clock *Clock) Paused() bool {
+	return clock.paused
+}
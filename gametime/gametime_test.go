@@ -0,0 +1,36 @@
+package gametime
+
+import "testing"
+
+func TestClockStandsStillWhilePaused(t *testing.T) {
+	source := 10.0
+	clock := New(func() float64 { return source })
+	steps := []struct {
+		advance float64
+		paused  bool
+		want    float64
+	}{
+		{0, false, 10},
+		{2, false, 12},
+		{0, true, 12},
+		{5, true, 12},
+		{0, false, 12},
+		{1, false, 13},
+		// Pausing twice keeps the first pause
+		{0, true, 13},
+		{3, true, 13},
+		{0, true, 13},
+		{4, false, 13},
+		{0.5, false, 13.5},
+	}
+	for i, step := range steps {
+		source += step.advance
+		clock.SetPaused(step.paused)
+		if got := clock.Now(); got != step.want {
+			t.Fatalf("step %d: time %v, want %v", i, got, step.want)
+		}
+		if clock.Paused() != step.paused {
+			t.Fatalf("step %d: paused %v, want %v", i, clock.Paused(), step.paused)
+		}
+	}
+}
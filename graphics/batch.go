@@ -0,0 +1,86 @@
+package graphics
+
+import (
+	"snakegame/render"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Floats per quad instance: transform matrix, uv rect and tint
+const instanceSize = 16 + 4 + 4
+
+// FrameStats counts the GPU work submitted during one frame
+type FrameStats struct {
+	DrawCalls int
+	Quads     int
+}
+
+var frameStats, lastFrameStats FrameStats
+
+// LastFrameStats returns the counters of the last completed frame
+func LastFrameStats() FrameStats {
+	return lastFrameStats
+}
+
+// SpriteBatch accumulates quads sharing a texture and submits them
+// with a single instanced draw call
+type SpriteBatch struct {
+	texture           uint32
+	instances         []float32
+	vertexArrayObject uint32
+	instanceBuffer    uint32
+}
+
+var batch *SpriteBatch
+
+func newSpriteBatch(vertexArrayObject uint32) *SpriteBatch {
+	batch := SpriteBatch{vertexArrayObject: vertexArrayObject}
+	gl.BindVertexArray(vertexArrayObject)
+	gl.GenBuffers(1, &batch.instanceBuffer)
+	gl.BindBuffer(gl.ARRAY_BUFFER, batch.instanceBuffer)
+
+	stride := int32(instanceSize * 4)
+	// Matrix columns at locations 2-5, then uv rect and tint
+	for i := uint32(0); i < 6; i++ {
+		gl.VertexAttribPointerWithOffset(2+i, 4, gl.FLOAT, false, stride, uintptr(i*16))
+		gl.EnableVertexAttribArray(2 + i)
+		gl.VertexAttribDivisor(2+i, 1)
+	}
+	gl.BindVertexArray(0)
+	return &batch
+}
+
+func (batch *SpriteBatch) Add(sprite render.Sprite, transform mgl32.Mat4, tint mgl32.Vec4) {
+	if len(batch.instances) > 0 && batch.texture != sprite.Texture {
+		batch.Flush()
+	}
+	batch.texture = sprite.Texture
+	batch.instances = append(batch.instances, transform[:]...)
+	batch.instances = append(batch.instances, sprite.UV[:]...)
+	batch.instances = append(batch.instances, tint[:]...)
+}
+
+// Flush draws all queued quads and empties the batch
+func (batch *SpriteBatch) Flush() {
+	count := len(batch.instances) / instanceSize
+	if count == 0 {
+		return
+	}
+	gl.UseProgram(spriteProgram.id)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, batch.texture)
+	gl.BindVertexArray(batch.vertexArrayObject)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, batch.instanceBuffer)
+	// Orphan the previous storage so the driver doesn't stall on it
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(batch.instances), nil, gl.STREAM_DRAW)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(batch.instances), gl.Ptr(batch.instances))
+
+	gl.DrawArraysInstanced(gl.TRIANGLES, 0, 6, int32(count))
+	gl.BindVertexArray(0)
+
+	frameStats.DrawCalls++
+	frameStats.Quads += count
+	batch.instances = batch.instances[:0]
+}
@@ -2,7 +2,9 @@ package graphics
 
 import (
 	"fmt"
+	"snakegame/assets"
 	"snakegame/helpers"
+	"snakegame/render"
 	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -153,7 +155,8 @@ const (
 )
 
 var window *glfw.Window
-var program, vertexArrayObject uint32
+var vertexArrayObject uint32
+var spriteProgram *shaderProgram
 var vertices = []float32{
 	//vertices coords              texture coords
 	0, 1, 0.0 /* top left */, 0.0, 1.0,
@@ -165,37 +168,6 @@ var vertices = []float32{
 	0, 0, 0.0 /* bottom left */, 0.0, 0.0,
 }
 
-const (
-	vertexShaderSource = `
-	#version 410
-    layout (location = 0) in vec3 aPos;
-	layout (location = 1) in vec2 aTexCoord;
-
-	out vec2 texCoord;
-
-	uniform mat4 transformMatrix;
-
-    void main()
-    {
-       gl_Position = transformMatrix*vec4(aPos.x, aPos.y, aPos.z, 1.0);
-	   texCoord = aTexCoord;
-    }
-	` + "\x00"
-
-	fragmentShaderSource = `
-	#version 410
-	in vec2 texCoord;
-
-	out vec4 FragmentColor;
-
-	uniform sampler2D texture1;
-
-	void main() {
-		FragmentColor=texture(texture1, texCoord);
-	}
-	` + "\x00"
-)
-
 func Init(windowName string, windowWidth, windowHeight int) error {
 	err := glfw.Init()
 	if err != nil {
@@ -207,12 +179,19 @@ func Init(windowName string, windowWidth, windowHeight int) error {
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 
+	// Keep the window about the same physical size on HiDPI screens
+	scale := MonitorScale(glfw.GetPrimaryMonitor())
+	windowWidth = int(float64(windowWidth) * scale)
+	windowHeight = int(float64(windowHeight) * scale)
 	window, err = glfw.CreateWindow(windowWidth, windowHeight, windowName, nil, nil)
 	if err != nil {
 		return err
 	}
 	window.MakeContextCurrent()
 	glfw.SwapInterval(1)
+	width, height := window.GetFramebufferSize()
+	viewport = [4]int32{0, 0, int32(width), int32(height)}
+	trackWindowedGeometry()
 
 	err = gl.Init()
 	if err != nil {
@@ -222,21 +201,17 @@ func Init(windowName string, windowWidth, windowHeight int) error {
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 
-	vertexShader, err := createShader(Vertex, vertexShaderSource)
-	if err != nil {
-		return err
-	}
-
-	fragmentShader, err := createShader(Fragment, fragmentShaderSource)
+	spriteProgram, err = loadShaderProgram("shaders/sprite.vert", "shaders/sprite.frag")
 	if err != nil {
 		return err
 	}
-	program, err = createProgram(vertexShader, fragmentShader)
+	err = initPostProcessing()
 	if err != nil {
 		return err
 	}
 
 	vertexArrayObject = createVAO(vertices)
+	batch = newSpriteBatch(vertexArrayObject)
 
 	return nil
 }
@@ -245,12 +220,16 @@ func Terminate() {
 	glfw.Terminate()
 }
 
+// SetResizeWindowCallback gets the framebuffer size in pixels,
+// which differs from the window size on HiDPI screens
 func SetResizeWindowCallback(callback func(width, height int) (startX, startY, newWidth, newHeight int32)) {
 	framebufferSizeCallback := func(w *glfw.Window, width int, height int) {
 		startX, startY, nWidth, nHeight := callback(width, height)
-		gl.Viewport(startX, startY, nWidth, nHeight)
+		viewport = [4]int32{startX, startY, nWidth, nHeight}
 	}
 	window.SetFramebufferSizeCallback(framebufferSizeCallback)
+	width, height := window.GetFramebufferSize()
+	framebufferSizeCallback(window, width, height)
 }
 
 func SetKeyInputCallback(callback func(keyValue KeyValue, keyAction KeyAction)) {
@@ -260,11 +239,19 @@ func SetKeyInputCallback(callback func(keyValue KeyValue, keyAction KeyAction))
 	window.SetKeyCallback(keyInputCallback)
 }
 
+func SetClipboard(text string) {
+	window.SetClipboardString(text)
+}
+
 func MainLoop(gameLogic func()) {
 	for !window.ShouldClose() {
-		gl.ClearColor(0.0, 1.0, 1.0, 1.0)
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		now := glfw.GetTime()
+		reloadChangedShaders(now)
+		frameStats = FrameStats{}
+		beginFrame()
 		gameLogic()
+		endFrame(now)
+		lastFrameStats = frameStats
 		window.SwapBuffers()
 		glfw.PollEvents()
 	}
@@ -292,7 +279,7 @@ func createShader(shaderType ShaderType, shaderSource string) (uint32, error) {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
 
-		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
+		return 0, fmt.Errorf("failed to compile: %v", log)
 	}
 	return shader, nil
 }
@@ -333,24 +320,53 @@ func createVAO(vertices []float32) uint32 {
 	return vertexArrayObject
 }
 
-func LoadTexture(imgPath string) uint32 {
+// LoadTexture uploads an asset found by assets.Open
+func LoadTexture(imgPath string) (uint32, error) {
+	imgBytes, width, height, err := assets.LoadImage(imgPath)
+	if err != nil {
+		return 0, err
+	}
+	return createTexture(imgBytes, width, height), nil
+}
+
+func createTexture(imgBytes []uint8, width, height int32) uint32 {
 	var texture uint32
-	imgBytes, width, height := helpers.LoadImage(imgPath)
 	imgBytes = helpers.ReflectImageVertically(imgBytes, width, true)
 	gl.GenTextures(1, &texture)
 	gl.BindTexture(gl.TEXTURE_2D, texture)
 
 	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(imgBytes))
-	gl.GenerateMipmap(gl.TEXTURE_2D)
+	// Mipmaps and repeat wrapping would bleed neighbour atlas sprites in
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 	return texture
 }
 
 func Draw(texture uint32, transform mgl32.Mat4) {
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
-	gl.UniformMatrix4fv(gl.GetUniformLocation(program, gl.Str("transformMatrix\x00")), 1, false, &transform[0])
-	gl.UseProgram(program)
-	gl.BindVertexArray(vertexArrayObject)
-	gl.DrawArrays(gl.TRIANGLES, 0, 6)
-	gl.BindVertexArray(0)
+	DrawSprite(render.Sprite{Texture: texture, UV: render.FullTextureUV}, transform)
+}
+
+func DrawSprite(sprite render.Sprite, transform mgl32.Mat4) {
+	DrawSpriteTinted(sprite, transform, render.WhiteTint)
+}
+
+// DrawSpriteTinted queues the sprite, its color is multiplied by tint
+func DrawSpriteTinted(sprite render.Sprite, transform mgl32.Mat4, tint mgl32.Vec4) {
+	batch.Add(sprite, transform, tint)
+}
+
+// Renderer exposes the window as a render.Renderer
+type Renderer struct{}
+
+func (Renderer) CreateTexture(imgBytes []uint8, width, height int32) uint32 {
+	return createTexture(imgBytes, width, height)
+}
+
+func (Renderer) DeleteTexture(texture uint32) {
+	gl.DeleteTextures(1, &texture)
+}
+
+func (Renderer) DrawSprite(sprite render.Sprite, transform mgl32.Mat4, tint mgl32.Vec4) {
+	DrawSpriteTinted(sprite, transform, tint)
 }
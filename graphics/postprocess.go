@@ -0,0 +1,171 @@
+package graphics
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Names of the post-processing effects, each one is shaders/<name>.frag
+var PostEffectNames = []string{"crt", "bloom"}
+
+// renderTarget is a framebuffer rendering into a texture
+type renderTarget struct {
+	framebuffer uint32
+	texture     uint32
+	width       int32
+	height      int32
+}
+
+func (target *renderTarget) resize(width, height int32) {
+	if target.width == width && target.height == height {
+		return
+	}
+	if target.framebuffer != 0 {
+		gl.DeleteFramebuffers(1, &target.framebuffer)
+		gl.DeleteTextures(1, &target.texture)
+	}
+	target.width, target.height = width, height
+
+	gl.GenTextures(1, &target.texture)
+	gl.BindTexture(gl.TEXTURE_2D, target.texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	gl.GenFramebuffers(1, &target.framebuffer)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.framebuffer)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, target.texture, 0)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// Letterboxed area of the window the game is shown in
+var viewport [4]int32
+
+var sceneTarget, pingTarget, pongTarget renderTarget
+var postVertexArrayObject uint32
+var postEffects = map[string]*shaderProgram{}
+var enabledEffects []*shaderProgram
+var compositeProgram *shaderProgram
+
+// Death feedback, both fade out over their duration
+var shakeStrength float32
+var shakeStart, shakeDuration float64
+var flashStart, flashDuration float64
+
+func initPostProcessing() error {
+	postVertexArrayObject = createVAO(vertices)
+	var err error
+	compositeProgram, err = loadShaderProgram("shaders/post.vert", "shaders/composite.frag")
+	if err != nil {
+		return err
+	}
+	for _, name := range PostEffectNames {
+		postEffects[name], err = loadShaderProgram("shaders/post.vert", "shaders/"+name+".frag")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPostEffects enables effects by name, applied in the given order
+func SetPostEffects(names []string) error {
+	var effects []*shaderProgram
+	for _, name := range names {
+		effect, ok := postEffects[name]
+		if !ok {
+			return fmt.Errorf("unknown post effect %q", name)
+		}
+		effects = append(effects, effect)
+	}
+	enabledEffects = effects
+	return nil
+}
+
+// Shake moves the frame randomly, strength is in window halves
+func Shake(strength float32, duration float64) {
+	shakeStrength = strength
+	shakeStart = glfw.GetTime()
+	shakeDuration = duration
+}
+
+// Flash whitens the frame
+func Flash(duration float64) {
+	flashStart = glfw.GetTime()
+	flashDuration = duration
+}
+
+// fading returns 1 at start, going down to 0 after duration
+func fading(now, start, duration float64) float32 {
+	if duration <= 0 || now-start >= duration {
+		return 0
+	}
+	return float32(1 - (now-start)/duration)
+}
+
+// beginFrame makes the game draw into the scene texture
+func beginFrame() {
+	sceneTarget.resize(viewport[2], viewport[3])
+	pingTarget.resize(viewport[2], viewport[3])
+	pongTarget.resize(viewport[2], viewport[3])
+	gl.BindFramebuffer(gl.FRAMEBUFFER, sceneTarget.framebuffer)
+	gl.Viewport(0, 0, sceneTarget.width, sceneTarget.height)
+	gl.ClearColor(letterboxColor.X(), letterboxColor.Y(), letterboxColor.Z(), 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
+
+// endFrame runs the effect chain and shows the result in the window
+func endFrame(now float64) {
+	batch.Flush()
+	gl.Disable(gl.BLEND)
+
+	source := sceneTarget.texture
+	targets := [2]*renderTarget{&pingTarget, &pongTarget}
+	for i, effect := range enabledEffects {
+		target := targets[i%2]
+		gl.BindFramebuffer(gl.FRAMEBUFFER, target.framebuffer)
+		drawFullscreen(effect, source, 0, 0, now)
+		source = target.texture
+	}
+
+	// Fill the whole window first, the game area covers the middle
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	width, height := window.GetFramebufferSize()
+	gl.Viewport(0, 0, int32(width), int32(height))
+	gl.ClearColor(letterboxColor.X(), letterboxColor.Y(), letterboxColor.Z(), 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.UseProgram(compositeProgram.id)
+	if letterboxTexture != 0 {
+		gl.Uniform1f(compositeProgram.uniform("flash"), 0)
+		gl.Uniform1f(compositeProgram.uniform("brightness"), 0.35)
+		drawFullscreen(compositeProgram, letterboxTexture, 0, 0, now)
+	}
+
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	shake := shakeStrength * fading(now, shakeStart, shakeDuration)
+	offsetX := (rand.Float32()*2 - 1) * shake
+	offsetY := (rand.Float32()*2 - 1) * shake
+	gl.Uniform1f(compositeProgram.uniform("flash"), fading(now, flashStart, flashDuration))
+	gl.Uniform1f(compositeProgram.uniform("brightness"), 1)
+	drawFullscreen(compositeProgram, source, offsetX, offsetY, now)
+
+	gl.Enable(gl.BLEND)
+}
+
+func drawFullscreen(shader *shaderProgram, texture uint32, offsetX, offsetY float32, now float64) {
+	gl.UseProgram(shader.id)
+	gl.Uniform2f(shader.uniform("offset"), offsetX, offsetY)
+	gl.Uniform2f(shader.uniform("resolution"), float32(viewport[2]), float32(viewport[3]))
+	gl.Uniform1f(shader.uniform("time"), float32(now))
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	gl.BindVertexArray(postVertexArrayObject)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	gl.BindVertexArray(0)
+	frameStats.DrawCalls++
+}
@@ -0,0 +1,135 @@
+package graphics
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"snakegame/assets"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// How often shader files on disk are checked for changes
+const shaderCheckPeriod = 1.0
+
+// shaderProgram is compiled from files found by assets.Open
+// and rebuilt when an override file on disk changes. The embedded
+// defaults are never watched, only a copy in the -assets or config
+// directory reloads, and it is picked up once it appears.
+type shaderProgram struct {
+	vertexFile   string
+	fragmentFile string
+	id           uint32
+	uniforms     map[string]int32
+	modified     time.Time
+}
+
+var shaderPrograms []*shaderProgram
+var lastShaderCheck float64
+
+func loadShaderProgram(vertexFile, fragmentFile string) (*shaderProgram, error) {
+	shader := shaderProgram{vertexFile: vertexFile, fragmentFile: fragmentFile}
+	shader.modified = shader.lastModified()
+	err := shader.compile()
+	if err != nil {
+		return nil, err
+	}
+	shaderPrograms = append(shaderPrograms, &shader)
+	return &shader, nil
+}
+
+func (shader *shaderProgram) compile() error {
+	vertexSource, err := readShader(shader.vertexFile)
+	if err != nil {
+		return err
+	}
+	fragmentSource, err := readShader(shader.fragmentFile)
+	if err != nil {
+		return err
+	}
+
+	vertexShader, err := createShader(Vertex, vertexSource)
+	if err != nil {
+		return fmt.Errorf("%s: %w", shader.vertexFile, err)
+	}
+	defer gl.DeleteShader(vertexShader)
+	fragmentShader, err := createShader(Fragment, fragmentSource)
+	if err != nil {
+		return fmt.Errorf("%s: %w", shader.fragmentFile, err)
+	}
+	defer gl.DeleteShader(fragmentShader)
+	program, err := createProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return fmt.Errorf("%s, %s: %w", shader.vertexFile, shader.fragmentFile, err)
+	}
+
+	if shader.id != 0 {
+		gl.DeleteProgram(shader.id)
+	}
+	shader.id = program
+	shader.uniforms = make(map[string]int32)
+	return nil
+}
+
+func readShader(name string) (string, error) {
+	f, err := assets.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	source, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	return string(source) + "\x00", nil
+}
+
+// uniform returns the cached uniform location, -1 if the program lacks it
+func (shader *shaderProgram) uniform(name string) int32 {
+	location, ok := shader.uniforms[name]
+	if !ok {
+		location = gl.GetUniformLocation(shader.id, gl.Str(name+"\x00"))
+		shader.uniforms[name] = location
+	}
+	return location
+}
+
+// lastModified is the newest time of the override files, zero if embedded
+func (shader *shaderProgram) lastModified() time.Time {
+	var modified time.Time
+	for _, name := range []string{shader.vertexFile, shader.fragmentFile} {
+		path := assets.Find(name)
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err == nil && info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+	}
+	return modified
+}
+
+// reloadChangedShaders recompiles edited programs,
+// a failed compile keeps the last good program running
+func reloadChangedShaders(now float64) {
+	if now-lastShaderCheck < shaderCheckPeriod {
+		return
+	}
+	lastShaderCheck = now
+	for _, shader := range shaderPrograms {
+		modified := shader.lastModified()
+		if modified.Equal(shader.modified) {
+			continue
+		}
+		shader.modified = modified
+		err := shader.compile()
+		if err != nil {
+			log.Printf("shader reload failed, keeping the last good program: %v", err)
+			continue
+		}
+		log.Printf("reloaded %s, %s", shader.vertexFile, shader.fragmentFile)
+	}
+}
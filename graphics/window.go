@@ -0,0 +1,167 @@
+package graphics
+
+import (
+	"log"
+	"math"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type WindowMode int
+
+const (
+	Windowed WindowMode = iota
+	// Borderless fullscreen keeps the desktop video mode
+	Borderless
+	// Fullscreen switches the monitor to its largest video mode
+	Fullscreen
+)
+
+var windowModeNames = []string{"windowed", "borderless", "fullscreen"}
+
+func (mode WindowMode) String() string {
+	if mode < 0 || int(mode) >= len(windowModeNames) {
+		return windowModeNames[Windowed]
+	}
+	return windowModeNames[mode]
+}
+
+// ParseWindowMode returns Windowed for unknown names
+func ParseWindowMode(name string) WindowMode {
+	for i, modeName := range windowModeNames {
+		if modeName == name {
+			return WindowMode(i)
+		}
+	}
+	return Windowed
+}
+
+// WindowGeometry is the window position and size in screen coords
+type WindowGeometry struct {
+	X, Y, Width, Height int
+}
+
+var windowMode = Windowed
+var windowMonitor int
+
+// Geometry restored when leaving fullscreen
+var windowedGeometry WindowGeometry
+
+// Color of the bars around the square game area
+var letterboxColor = mgl32.Vec3{0, 0, 0}
+var letterboxTexture uint32
+
+func trackWindowedGeometry() {
+	x, y := window.GetPos()
+	width, height := window.GetSize()
+	windowedGeometry = WindowGeometry{x, y, width, height}
+	window.SetPosCallback(func(w *glfw.Window, x, y int) {
+		if windowMode == Windowed {
+			windowedGeometry.X, windowedGeometry.Y = x, y
+		}
+	})
+	window.SetSizeCallback(func(w *glfw.Window, width, height int) {
+		if windowMode == Windowed {
+			windowedGeometry.Width, windowedGeometry.Height = width, height
+		}
+	})
+}
+
+// MonitorScale guesses the HiDPI factor from the monitor physical size,
+// 1 for a regular 96 dpi screen
+func MonitorScale(monitor *glfw.Monitor) float64 {
+	widthMM, _ := monitor.GetPhysicalSize()
+	mode := monitor.GetVideoMode()
+	if widthMM <= 0 || mode == nil {
+		return 1
+	}
+	dpi := float64(mode.Width) / (float64(widthMM) / 25.4)
+	// Round to halves, 1.5 and 2 are the common scales
+	return math.Max(1, math.Round(dpi/96*2)/2)
+}
+
+func MonitorNames() []string {
+	var names []string
+	for _, monitor := range glfw.GetMonitors() {
+		names = append(names, monitor.GetName())
+	}
+	return names
+}
+
+func monitorAt(index int) *glfw.Monitor {
+	monitors := glfw.GetMonitors()
+	if index < 0 || index >= len(monitors) {
+		return glfw.GetPrimaryMonitor()
+	}
+	return monitors[index]
+}
+
+func GetWindowMode() (mode WindowMode, monitor int) {
+	return windowMode, windowMonitor
+}
+
+// SetWindowMode switches between windowed and fullscreen modes
+// on the monitor with the given index
+func SetWindowMode(mode WindowMode, monitorIndex int) {
+	monitor := monitorAt(monitorIndex)
+	// A monitor going away can leave no video mode to fill
+	var current *glfw.VidMode
+	if monitor != nil {
+		current = monitor.GetVideoMode()
+	}
+	if current == nil && mode != Windowed {
+		log.Printf("monitor %d has no video mode, staying windowed", monitorIndex)
+		mode = Windowed
+	}
+	windowMonitor = monitorIndex
+	windowMode = mode
+	switch mode {
+	case Borderless:
+		window.SetMonitor(monitor, 0, 0, current.Width, current.Height, current.RefreshRate)
+	case Fullscreen:
+		best := current
+		for _, videoMode := range monitor.GetVideoModes() {
+			if videoMode.Width*videoMode.Height > best.Width*best.Height {
+				best = videoMode
+			}
+		}
+		window.SetMonitor(monitor, 0, 0, best.Width, best.Height, best.RefreshRate)
+	default:
+		geometry := windowedGeometry
+		// Keep the window on the chosen monitor
+		if current != nil {
+			monitorX, monitorY := monitor.GetPos()
+			if geometry.X < monitorX || geometry.X >= monitorX+current.Width ||
+				geometry.Y < monitorY || geometry.Y >= monitorY+current.Height {
+				geometry.X = monitorX + (current.Width-geometry.Width)/2
+				geometry.Y = monitorY + (current.Height-geometry.Height)/2
+			}
+		}
+		window.SetMonitor(nil, geometry.X, geometry.Y, geometry.Width, geometry.Height, 0)
+	}
+}
+
+func GetWindowedGeometry() WindowGeometry {
+	return windowedGeometry
+}
+
+// SetWindowedGeometry moves and resizes the window, used to restore
+// the geometry saved by the previous run
+func SetWindowedGeometry(geometry WindowGeometry) {
+	if geometry.Width <= 0 || geometry.Height <= 0 {
+		return
+	}
+	windowedGeometry = geometry
+	if windowMode == Windowed {
+		window.SetPos(geometry.X, geometry.Y)
+		window.SetSize(geometry.Width, geometry.Height)
+	}
+}
+
+// SetLetterbox sets what fills the window around the square game area,
+// the texture is stretched and dimmed, 0 leaves only the color
+func SetLetterbox(color mgl32.Vec3, texture uint32) {
+	letterboxColor = color
+	letterboxTexture = texture
+}
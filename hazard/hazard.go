@@ -0,0 +1,288 @@
+package hazard
+
+import (
+	"math"
+	"math/rand"
+	"snakegame/helpers"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type PoisonKind int
+
+const (
+	// Cuts segments off the tail
+	ShrinkPoison PoisonKind = iota
+	// Swaps the movement keys for a while
+	ReversePoison
+	PoisonKindCount
+)
+
+// PoisonType is what eating a kind of poison does
+type PoisonType struct {
+	Name string
+	// Segments cut off the tail
+	Shrink int
+	// Seconds the controls stay reversed
+	Reverse float64
+}
+
+var PoisonTypes = [PoisonKindCount]PoisonType{
+	ShrinkPoison:  {Name: "poison_shrink", Shrink: 2},
+	ReversePoison: {Name: "poison_reverse", Reverse: 5},
+}
+
+func (kind PoisonKind) Type() PoisonType {
+	return PoisonTypes[kind]
+}
+
+// Sprite name of the kind
+func (kind PoisonKind) String() string {
+	return PoisonTypes[kind].Name
+}
+
+// Seconds poison blinks before it can be eaten, and then stays
+const (
+	PoisonWarningTime = 1.0
+	PoisonLifetime    = 7.0
+)
+
+// Poison lies on the board for a while, harmless until ActiveAt
+type Poison struct {
+	Kind      PoisonKind `json:"kind"`
+	Position  mgl32.Vec2 `json:"position"`
+	ActiveAt  float64    `json:"activeAt"`
+	ExpiresAt float64    `json:"expiresAt"`
+}
+
+func (poison Poison) Active(now float64) bool {
+	return now >= poison.ActiveAt
+}
+
+// Seconds a spike trap blinks before it comes out
+const SpikeWarningTime = 0.75
+
+type SpikePhase int
+
+const (
+	SpikeOff SpikePhase = iota
+	SpikeWarning
+	SpikeOn
+)
+
+// Spike is a trap coming out for On seconds every Period seconds,
+// Offset shifts its rhythm against the other traps
+type Spike struct {
+	Position mgl32.Vec2 `json:"position"`
+	Period   float64    `json:"period"`
+	On       float64    `json:"on"`
+	Offset   float64    `json:"offset"`
+}
+
+// Phase returns the trap state levelTime seconds into the level
+func (spike Spike) Phase(levelTime float64) SpikePhase {
+	if spike.Period <= 0 {
+		return SpikeOn
+	}
+	t := math.Mod(levelTime+spike.Offset, spike.Period)
+	if t < 0 {
+		t += spike.Period
+	}
+	switch {
+	case t < spike.On:
+		return SpikeOn
+	case t >= spike.Period-SpikeWarningTime:
+		return SpikeWarning
+	}
+	return SpikeOff
+}
+
+// Portal links two cells, a head entering one comes out of the other.
+// Rotation is in quarter turns counterclockwise applied going from A
+// to B, the way back turns the other way.
+type Portal struct {
+	A        mgl32.Vec2 `json:"a"`
+	B        mgl32.Vec2 `json:"b"`
+	Rotation int        `json:"rotation"`
+}
+
+// Rotate turns a direction by quarter turns counterclockwise
+func Rotate(direction mgl32.Vec2, quarterTurns int) mgl32.Vec2 {
+	quarterTurns = ((quarterTurns % 4) + 4) % 4
+	for i := 0; i < quarterTurns; i++ {
+		direction = mgl32.Vec2{-direction.Y(), direction.X()}
+	}
+	return direction
+}
+
+// Level lists the hazards of a level
+type Level struct {
+	// Solid cells, running into one is like hitting the board edge
+	Walls   []mgl32.Vec2 `json:"walls"`
+	Spikes  []Spike      `json:"spikes"`
+	Portals []Portal     `json:"portals"`
+	// Seconds between poison spawns, 0 for none
+	PoisonPeriod float64 `json:"poisonPeriod"`
+}
+
+// State is the hazards of the current level
+type State struct {
+	Level Level `json:"level"`
+	// Time the level started, spike rhythms count from it
+	Start         float64  `json:"start"`
+	Poison        []Poison `json:"poison"`
+	ReversedStart float64  `json:"reversedStart"`
+	ReversedUntil float64  `json:"reversedUntil"`
+	nextPoison    float64
+	rand          *rand.Rand
+	// Wall cells by field index, built on first use after a reset
+	walls []bool
+}
+
+func NewState(seed int64) *State {
+	return &State{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Reset switches to the level hazards, starting their clock at now
+func (state *State) Reset(level Level, now float64) {
+	state.Level = level
+	state.walls = nil
+	state.Start = now
+	state.Poison = nil
+	state.ReversedStart = 0
+	state.ReversedUntil = 0
+	state.nextPoison = now + level.PoisonPeriod
+}
+
+// Update drops expired poison and every poison period
+// spawns a new one on one of the free cells
+func (state *State) Update(now float64, freeCells []int) {
+	poison := state.Poison[:0]
+	for _, item := range state.Poison {
+		if item.ExpiresAt > now {
+			poison = append(poison, item)
+		}
+	}
+	state.Poison = poison
+
+	period := state.Level.PoisonPeriod
+	if period <= 0 || now < state.nextPoison {
+		return
+	}
+	state.nextPoison = now + period
+	free := helpers.CellsDifference(freeCells, state.Indices())
+	if len(free) == 0 {
+		return
+	}
+	x, y := helpers.IndexToCoords(free[state.rand.Intn(len(free))])
+	state.Poison = append(state.Poison, Poison{
+		Kind:      PoisonKind(state.rand.Intn(int(PoisonKindCount))),
+		Position:  mgl32.Vec2{float32(x), float32(y)},
+		ActiveAt:  now + PoisonWarningTime,
+		ExpiresAt: now + PoisonWarningTime + PoisonLifetime,
+	})
+}
+
+// EatPoison takes the active poison on the cell off the board,
+// reversing the controls if it is that kind
+func (state *State) EatPoison(cell mgl32.Vec2, now float64) (Poison, bool) {
+	for i, item := range state.Poison {
+		if !item.Active(now) || item.Position != cell {
+			continue
+		}
+		state.Poison = append(state.Poison[:i], state.Poison[i+1:]...)
+		if reverse := item.Kind.Type().Reverse; reverse > 0 {
+			state.ReversedStart = now
+			state.ReversedUntil = now + reverse
+		}
+		return item, true
+	}
+	return Poison{}, false
+}
+
+func (state *State) Reversed(now float64) bool {
+	return now < state.ReversedUntil
+}
+
+// ReversedRemaining returns the part of the reversed controls time left
+func (state *State) ReversedRemaining(now float64) float32 {
+	if !state.Reversed(now) {
+		return 0
+	}
+	return float32((state.ReversedUntil - now) / (state.ReversedUntil - state.ReversedStart))
+}
+
+// WallAt reports whether a wall stands on the cell
+func (state *State) WallAt(cell mgl32.Vec2) bool {
+	x, y := int(cell.X()), int(cell.Y())
+	if !helpers.InField(x, y) || float32(x) != cell.X() || float32(y) != cell.Y() {
+		return false
+	}
+	if state.walls == nil {
+		state.walls = make([]bool, helpers.FieldSize*helpers.FieldSize)
+		for _, wall := range state.Level.Walls {
+			if helpers.InField(int(wall.X()), int(wall.Y())) {
+				state.walls[helpers.CoordsToIndex(int(wall.X()), int(wall.Y()))] = true
+			}
+		}
+	}
+	return state.walls[helpers.CoordsToIndex(x, y)]
+}
+
+// SpikeAt reports whether a trap is out on the cell
+func (state *State) SpikeAt(cell mgl32.Vec2, now float64) bool {
+	for _, spike := range state.Level.Spikes {
+		if spike.Position == cell && spike.Phase(now-state.Start) == SpikeOn {
+			return true
+		}
+	}
+	return false
+}
+
+// Portal returns where a head entering the cell comes out
+// and the quarter turns applied to its direction
+func (state *State) Portal(cell mgl32.Vec2) (exit mgl32.Vec2, rotation int, ok bool) {
+	for _, portal := range state.Level.Portals {
+		switch cell {
+		case portal.A:
+			return portal.B, portal.Rotation, true
+		case portal.B:
+			return portal.A, -portal.Rotation, true
+		}
+	}
+	return mgl32.Vec2{}, 0, false
+}
+
+// Indices returns the field cells taken by walls, spikes, portals
+// and poison, food is never placed on them
+func (state *State) Indices() []int {
+	var indices []int
+	add := func(cell mgl32.Vec2) {
+		indices = append(indices, helpers.CoordsToIndex(int(cell.X()), int(cell.Y())))
+	}
+	for _, wall := range state.Level.Walls {
+		add(wall)
+	}
+	for _, spike := range state.Level.Spikes {
+		add(spike.Position)
+	}
+	for _, portal := range state.Level.Portals {
+		add(portal.A)
+		add(portal.B)
+	}
+	for _, item := range state.Poison {
+		add(item.Position)
+	}
+	return indices
+}
+
+// Copy returns the hazards as they are now, for replays
+func (state *State) Copy() State {
+	return State{
+		Level:         state.Level,
+		Start:         state.Start,
+		Poison:        append([]Poison(nil), state.Poison...),
+		ReversedStart: state.ReversedStart,
+		ReversedUntil: state.ReversedUntil,
+	}
+}
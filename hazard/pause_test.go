@@ -0,0 +1,64 @@
+package hazard_test
+
+import (
+	"snakegame/gametime"
+	"snakegame/hazard"
+	"snakegame/helpers"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The game times hazards with its clock, which stands still while paused
+func TestHazardsWaitOutAPause(t *testing.T) {
+	window := 100.0
+	clock := gametime.New(func() float64 { return window })
+	spike := hazard.Spike{Position: mgl32.Vec2{1, 1}, Period: 4, On: 1}
+	state := hazard.NewState(1)
+	state.Reset(hazard.Level{Spikes: []hazard.Spike{spike}, PoisonPeriod: 3}, clock.Now())
+	freeCells := []int{helpers.CoordsToIndex(3, 3), helpers.CoordsToIndex(4, 4)}
+
+	window += 3.5
+	state.Update(clock.Now(), freeCells)
+	if len(state.Poison) != 1 {
+		t.Fatalf("%d poison after the first period, want 1", len(state.Poison))
+	}
+	first := state.Poison[0]
+	reverse := hazard.Poison{Kind: hazard.ReversePoison, Position: mgl32.Vec2{4, 4}, ExpiresAt: clock.Now() + 1}
+	state.Poison = append(state.Poison, reverse)
+	if _, ok := state.EatPoison(reverse.Position, clock.Now()); !ok {
+		t.Fatal("the reverse poison can't be eaten")
+	}
+
+	window += 0.25
+	phase := spike.Phase(clock.Now() - state.Start)
+	clock.SetPaused(true)
+	window += 1000.5
+	now := clock.Now()
+	state.Update(now, freeCells)
+	if len(state.Poison) != 1 {
+		t.Errorf("%d poison after a pause, want the same one", len(state.Poison))
+	}
+	if !state.Reversed(now) {
+		t.Error("the controls turned back during the pause")
+	}
+	if got := spike.Phase(now - state.Start); got != phase {
+		t.Errorf("spike phase %v after a pause, want %v", got, phase)
+	}
+	if state.SpikeAt(spike.Position, now) != (phase == hazard.SpikeOn) {
+		t.Error("the spike moved during the pause")
+	}
+
+	clock.SetPaused(false)
+	window += hazard.PoisonWarningTime + hazard.PoisonLifetime
+	now = clock.Now()
+	state.Update(now, freeCells)
+	if state.Reversed(now) {
+		t.Error("the controls are still reversed once the game went on")
+	}
+	for _, poison := range state.Poison {
+		if poison == first {
+			t.Errorf("poison %v outlived its lifetime", poison)
+		}
+	}
+}
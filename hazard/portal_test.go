@@ -0,0 +1,113 @@
+package hazard_test
+
+import (
+	"snakegame/hazard"
+	"snakegame/snakemodule"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func vecs(coords ...[2]float32) []mgl32.Vec2 {
+	list := make([]mgl32.Vec2, len(coords))
+	for i, c := range coords {
+		list[i] = mgl32.Vec2{c[0], c[1]}
+	}
+	return list
+}
+
+func portalLevel() *hazard.State {
+	state := hazard.NewState(1)
+	state.Reset(hazard.Level{Portals: []hazard.Portal{{A: mgl32.Vec2{2, 5}, B: mgl32.Vec2{7, 5}}}}, 0)
+	return state
+}
+
+// step moves the head one cell the way the game does, through
+// the portal when it steps onto one
+func step(state *hazard.State, snake *snakemodule.Snake, direction mgl32.Vec2) {
+	head := snake.GetHead()
+	target := head.GetCoords().Add(direction)
+	if exit, rotation, ok := state.Portal(target); ok {
+		snake.MoveVia(exit, direction, hazard.Rotate(direction, rotation))
+		return
+	}
+	snake.Move(target)
+}
+
+func TestSnakeSplitAcrossPortal(t *testing.T) {
+	state := portalLevel()
+	snake := snakemodule.NewSnake(vecs(
+		[2]float32{0, 0}, [2]float32{0, 1}, [2]float32{0, 2}, [2]float32{0, 3},
+		[2]float32{0, 4}, [2]float32{0, 5}, [2]float32{1, 5}))
+	right := mgl32.Vec2{1, 0}
+	for i := 0; i < 3; i++ {
+		step(state, snake, right)
+	}
+
+	want := vecs([2]float32{0, 3}, [2]float32{0, 4}, [2]float32{0, 5}, [2]float32{1, 5},
+		[2]float32{7, 5}, [2]float32{8, 5}, [2]float32{9, 5})
+	got := snake.Cells()
+	if len(got) != len(want) {
+		t.Fatalf("cells = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("cells = %v, want %v", got, want)
+		}
+	}
+	if snake.Occupies(mgl32.Vec2{2, 5}) {
+		t.Error("the entry cell is taken, the body skips it")
+	}
+
+	// The segment before the gap runs straight into the portal
+	var parts []snakemodule.BodyPart
+	snake.Draw(func(part snakemodule.BodyPart, vec mgl32.Vec2, angle float32) {
+		parts = append(parts, part)
+	})
+	if parts[3] != snakemodule.Straight || parts[4] != snakemodule.Straight {
+		t.Errorf("segments around the portal are %v and %v, want straight", parts[3], parts[4])
+	}
+}
+
+func TestBlockedPortalExit(t *testing.T) {
+	state := portalLevel()
+	down := mgl32.Vec2{0, -1}
+	tests := []struct {
+		name string
+		body []mgl32.Vec2
+		want bool
+	}{
+		{
+			"body across the exit",
+			vecs([2]float32{7, 3}, [2]float32{7, 4}, [2]float32{7, 5}, [2]float32{7, 6},
+				[2]float32{6, 6}, [2]float32{5, 6}, [2]float32{4, 6}, [2]float32{3, 6}, [2]float32{2, 6}),
+			true,
+		},
+		{
+			"tail on the exit",
+			vecs([2]float32{7, 5}, [2]float32{7, 6}, [2]float32{6, 6}, [2]float32{5, 6},
+				[2]float32{4, 6}, [2]float32{3, 6}, [2]float32{2, 6}),
+			false,
+		},
+		{
+			"exit clear",
+			vecs([2]float32{4, 6}, [2]float32{3, 6}, [2]float32{2, 6}),
+			false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snake := snakemodule.NewSnake(test.body)
+			head := snake.GetHead()
+			exit, _, ok := state.Portal(head.GetCoords().Add(down))
+			if !ok {
+				t.Fatal("the head isn't stepping onto the portal")
+			}
+			// The game kills the snake when the exit is blocked
+			// rather than moving the head onto its body
+			if got := snake.Blocked(exit); got != test.want {
+				t.Errorf("exit %v blocked = %v, want %v", exit, got, test.want)
+			}
+		})
+	}
+}
@@ -16,16 +16,42 @@ func CellsDifference(firstSlice, secondSlice []int) []int {
 	return diffCells
 }
 
+// Cells per side of the field
+const FieldSize = 10
+
 func CoordsToIndex(x, y int) int {
-	return y*10 + x
+	return y*FieldSize + x
 }
 
 func IndexToCoords(i int) (x, y int) {
-	x = i % 10
-	y = i / 10
+	x = i % FieldSize
+	y = i / FieldSize
 	return
 }
 
+func InField(x, y int) bool {
+	return x >= 0 && x < FieldSize && y >= 0 && y < FieldSize
+}
+
 func Distance(coord1, coord2 mgl32.Vec2) float32 {
 	return coord1.Sub(coord2).Len()
 }
+
+// Neighbours returns the indices of the cells sharing a side with cell i
+func Neighbours(i int) []int {
+	x, y := IndexToCoords(i)
+	var neighbours []int
+	if x > 0 {
+		neighbours = append(neighbours, CoordsToIndex(x-1, y))
+	}
+	if x < FieldSize-1 {
+		neighbours = append(neighbours, CoordsToIndex(x+1, y))
+	}
+	if y > 0 {
+		neighbours = append(neighbours, CoordsToIndex(x, y-1))
+	}
+	if y < FieldSize-1 {
+		neighbours = append(neighbours, CoordsToIndex(x, y+1))
+	}
+	return neighbours
+}
@@ -1,33 +1,29 @@
 package helpers
 
 import (
+	"errors"
 	"image"
 	"image/draw"
-	"os"
+	"io"
 
 	_ "image/jpeg"
 	_ "image/png"
 )
 
-func LoadImage(path string) ([]uint8, int32, int32) {
-	f, err := os.Open(path)
+// DecodeImage converts a png or jpeg image into RGBA pixels
+func DecodeImage(r io.Reader) ([]uint8, int32, int32, error) {
+	img, _, err := image.Decode(r)
 	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	img, _, err := image.Decode(f)
-	if err != nil {
-		panic(err)
+		return nil, 0, 0, err
 	}
 	rgba := image.NewRGBA(img.Bounds())
 	if rgba.Stride != rgba.Rect.Size().X*4 {
-		panic("unsupported stride")
+		return nil, 0, 0, errors.New("unsupported stride")
 	}
 	width := int32(rgba.Rect.Size().X)
 	height := int32(rgba.Rect.Size().Y)
 	draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
-	return rgba.Pix, width, height
+	return rgba.Pix, width, height, nil
 }
 
 func ReflectImageVertically(imageData []uint8, width int32, alfa bool) []uint8 {
@@ -0,0 +1,70 @@
+package levelgen
+
+// grid tracks the walls of a board being generated
+type grid struct {
+	width  int
+	height int
+	wall   []bool
+	walls  int
+}
+
+func newGrid(width, height int) *grid {
+	return &grid{width: width, height: height, wall: make([]bool, width*height)}
+}
+
+func (board *grid) inside(x, y int) bool {
+	return x >= 0 && x < board.width && y >= 0 && y < board.height
+}
+
+func (board *grid) index(x, y int) int {
+	return y*board.width + x
+}
+
+func (board *grid) set(x, y int, wall bool) {
+	i := board.index(x, y)
+	if board.wall[i] == wall {
+		return
+	}
+	board.wall[i] = wall
+	if wall {
+		board.walls++
+	} else {
+		board.walls--
+	}
+}
+
+// connected flood fills from a free cell and reports whether
+// it reached every free cell
+func (board *grid) connected() bool {
+	start := -1
+	for i, wall := range board.wall {
+		if !wall {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return false
+	}
+	seen := make([]bool, len(board.wall))
+	seen[start] = true
+	stack := []int{start}
+	reached := 1
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		x, y := i%board.width, i/board.width
+		for _, next := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+			if !board.inside(next[0], next[1]) {
+				continue
+			}
+			j := board.index(next[0], next[1])
+			if !board.wall[j] && !seen[j] {
+				seen[j] = true
+				reached++
+				stack = append(stack, j)
+			}
+		}
+	}
+	return reached+board.walls == len(board.wall)
+}
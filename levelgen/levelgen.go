@@ -0,0 +1,136 @@
+package levelgen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Options of a generated layout, the same options give the same layout
+type Options struct {
+	Width  int
+	Height int
+	// 0 is an open board, 1 the most walls
+	Difficulty float64
+	// Snake cells at the start, along the bottom row heading right
+	SnakeLength int
+	// Food the level asks for, the snake needs room to grow by it
+	FoodQuota int
+	Seed      int64
+}
+
+// Layout is a board of walls, coords go from the bottom left corner
+type Layout struct {
+	Width  int
+	Height int
+	Walls  []mgl32.Vec2
+	// Snake cells from tail to head
+	Snake []mgl32.Vec2
+}
+
+// Cells kept clear in front of the snake head
+const corridorLength = 4
+
+// Longest straight wall piece
+const maxSegment = 4
+
+// Most of the board is left free even at the top difficulty
+const maxWallShare = 0.3
+
+// Generate lays out walls in short straight pieces. Every free cell
+// stays reachable from the others, the bottom rows in front of the
+// snake stay clear and there is room for the snake to grow by the quota.
+func Generate(options Options) (Layout, error) {
+	width, height := options.Width, options.Height
+	if width < 2 || height < 2 {
+		return Layout{}, errors.New("the board needs at least 2 by 2 cells")
+	}
+	if options.SnakeLength < 1 || options.SnakeLength+corridorLength > width {
+		return Layout{}, fmt.Errorf("a snake of %d doesn't fit a board %d wide", options.SnakeLength, width)
+	}
+	difficulty := options.Difficulty
+	if difficulty < 0 {
+		difficulty = 0
+	} else if difficulty > 1 {
+		difficulty = 1
+	}
+
+	layout := Layout{Width: width, Height: height}
+	for x := 0; x < options.SnakeLength; x++ {
+		layout.Snake = append(layout.Snake, mgl32.Vec2{float32(x), 0})
+	}
+	board := newGrid(width, height)
+	cells := width * height
+	// Room for the snake once it has eaten the quota, and for food
+	needed := options.SnakeLength + options.FoodQuota + 1
+	if needed > cells {
+		return Layout{}, fmt.Errorf("a snake of %d eating %d food doesn't fit a board of %d cells",
+			options.SnakeLength, options.FoodQuota, cells)
+	}
+	limit := int(float64(cells) * maxWallShare * difficulty)
+	if limit > cells-needed {
+		limit = cells - needed
+	}
+
+	r := rand.New(rand.NewSource(options.Seed))
+	for tries := 0; board.walls < limit && tries < cells*4; tries++ {
+		x, y := r.Intn(width), r.Intn(height)
+		dx, dy := 1, 0
+		if r.Intn(2) == 0 {
+			dx, dy = 0, 1
+		}
+		length := 1 + r.Intn(maxSegment)
+		for i := 0; i < length && board.walls < limit; i++ {
+			cx, cy := x+dx*i, y+dy*i
+			if !board.inside(cx, cy) || board.wall[board.index(cx, cy)] ||
+				inCorridor(cx, cy, options.SnakeLength) {
+				break
+			}
+			board.set(cx, cy, true)
+			if !board.connected() {
+				board.set(cx, cy, false)
+				break
+			}
+			layout.Walls = append(layout.Walls, mgl32.Vec2{float32(cx), float32(cy)})
+		}
+	}
+	return layout, nil
+}
+
+// The bottom two rows under and in front of the snake
+func inCorridor(x, y, snakeLength int) bool {
+	return y <= 1 && x < snakeLength+corridorLength
+}
+
+// String draws the layout with # for walls, o for the snake,
+// @ for its head and . for free cells, the top row first
+func (layout Layout) String() string {
+	rows := make([][]byte, layout.Height)
+	for y := range rows {
+		rows[y] = []byte(strings.Repeat(".", layout.Width))
+	}
+	for _, wall := range layout.Walls {
+		rows[int(wall.Y())][int(wall.X())] = '#'
+	}
+	for i, cell := range layout.Snake {
+		mark := byte('o')
+		if i == len(layout.Snake)-1 {
+			mark = '@'
+		}
+		rows[int(cell.Y())][int(cell.X())] = mark
+	}
+	var text strings.Builder
+	for y := layout.Height - 1; y >= 0; y-- {
+		text.Write(rows[y])
+		text.WriteByte('\n')
+	}
+	return text.String()
+}
+
+// FreeCells counts the cells without walls
+func (layout Layout) FreeCells() int {
+	return layout.Width*layout.Height - len(layout.Walls)
+}
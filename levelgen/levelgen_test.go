@@ -0,0 +1,85 @@
+package levelgen
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSameSeedSameLayout(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		options := Options{Width: 10, Height: 10, Difficulty: 0.8, SnakeLength: 3, FoodQuota: 15, Seed: seed}
+		first, err := Generate(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := Generate(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.String() != second.String() {
+			t.Errorf("seed %d gave two layouts:\n%s\n%s", seed, first, second)
+		}
+	}
+}
+
+func TestEveryFreeCellReachable(t *testing.T) {
+	tests := []Options{
+		{Width: 10, Height: 10, Difficulty: 1, SnakeLength: 3, FoodQuota: 15},
+		{Width: 10, Height: 10, Difficulty: 0.5, SnakeLength: 5, FoodQuota: 30},
+		{Width: 16, Height: 9, Difficulty: 1, SnakeLength: 4, FoodQuota: 10},
+		{Width: 6, Height: 12, Difficulty: 1, SnakeLength: 2, FoodQuota: 5},
+	}
+	for _, options := range tests {
+		for seed := int64(0); seed < 50; seed++ {
+			options.Seed = seed
+			layout, err := Generate(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reached := reachable(layout); reached != layout.FreeCells() {
+				t.Fatalf("%dx%d seed %d: %d of %d free cells reachable from the snake\n%s",
+					options.Width, options.Height, seed, reached, layout.FreeCells(), layout)
+			}
+			if free := layout.FreeCells(); free < options.SnakeLength+options.FoodQuota+1 {
+				t.Fatalf("%dx%d seed %d: %d free cells, no room for the quota", options.Width, options.Height, seed, free)
+			}
+		}
+	}
+}
+
+func TestQuotaThatDoesNotFit(t *testing.T) {
+	_, err := Generate(Options{Width: 10, Height: 10, SnakeLength: 3, FoodQuota: 97})
+	if err == nil {
+		t.Error("a quota filling more than the board was generated")
+	}
+	_, err = Generate(Options{Width: 10, Height: 10, Difficulty: 1, SnakeLength: 3, FoodQuota: 96})
+	if err != nil {
+		t.Errorf("a quota filling the board exactly: %v", err)
+	}
+}
+
+// reachable counts the free cells a flood from the snake head gets to
+func reachable(layout Layout) int {
+	walls := make(map[mgl32.Vec2]bool)
+	for _, wall := range layout.Walls {
+		walls[wall] = true
+	}
+	start := layout.Snake[len(layout.Snake)-1]
+	seen := map[mgl32.Vec2]bool{start: true}
+	queue := []mgl32.Vec2{start}
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+		for _, step := range []mgl32.Vec2{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			next := cell.Add(step)
+			x, y := int(next.X()), int(next.Y())
+			if next.X() < 0 || next.Y() < 0 || x >= layout.Width || y >= layout.Height || walls[next] || seen[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return len(seen)
+}
@@ -1,13 +1,32 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
 	"math"
 	"os"
 	"runtime"
+	"snakegame/animation"
+	"snakegame/assets"
+	"snakegame/collision"
+	"snakegame/daily"
+	"snakegame/events"
+	"snakegame/gametime"
 	"snakegame/graphics"
+	"snakegame/hazard"
+	"snakegame/helpers"
+	"snakegame/mode"
+	"snakegame/powerup"
+	"snakegame/replay"
+	"snakegame/rival"
+	"snakegame/scene"
+	"snakegame/settings"
 	"snakegame/snakemodule"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
@@ -33,7 +52,6 @@ var startGame = true
 var pauseGame = false
 var gameLevel int = 0
 var eatenFoodCounter int = 0
-var progressSaved = true
 var startLevel = true
 
 const (
@@ -51,206 +69,404 @@ var period float32
 
 // Speed settings
 var timeWindow float32 = 0.5
-var intersectionThreshold float32
-var higherEdge, lowerEdge float32
 var timeToMove = false
 
 // Field settings
 var fieldCells = make([]int, cellsNumber*cellsNumber)
 
 var snake *snakemodule.Snake
-var food snakemodule.Food
+var foods = snakemodule.NewFoodSet(time.Now().UnixNano())
 
 // Movement horizontal or vertical
 var horizontalMove = true
 
 var foodWasEaten = false
 
+// Seconds before expiring food starts blinking fast
+const foodExpiryWarning = 1.5
+
+// Spikes, portals and poison of the current level
+var hazards = hazard.NewState(time.Now().UnixNano())
+
+// Power-ups on the board and the active effects
+var powerUps = powerup.NewState(time.Now().UnixNano())
+
+// Effects running at the end of a level, they go on on the next one
+var carriedEffects []powerup.Effect
+
+// Computer snakes competing for the food
+var rivals = rival.NewGroup(time.Now().UnixNano())
+
+// Slow motion stretches the time between steps by this factor
+const slowMotionFactor = 1.6
+
+// Magnet reach in cells
+const magnetRadius = 3
+
+var gameScene *scene.Scene
+
+// Feedback for eating, levelling up and dying
+var animations = animation.NewSystem(time.Now().UnixNano())
+var frameTime float64
+
+// Game time, the window timer once there is one, standing still while
+// the game is paused. Screens rendered without a window are drawn at time 0.
+var gameTime = gametime.New(func() float64 { return 0 })
+
+// clock is the game time in seconds
+func clock() float64 {
+	return gameTime.Now()
+}
+
+var assetsDir = flag.String("assets", "", "directory with asset overrides, shaders in its shaders directory reload when edited (the built in ones never do, copy one there to work on it)")
+var debug = flag.Bool("debug", false, "log run results and, on F3, draw stats")
+var postEffects = flag.String("effects", "", "comma separated post effects: "+strings.Join(graphics.PostEffectNames, ", "))
+
+// Replay of the current life, exported on G after game over
+var recording = replay.NewRecording(cellsNumber, 10000)
+var replayPath = flag.String("replay", "replay.gif", "GIF file written on G after game over")
+var replayFramesDir = flag.String("replay-frames", "", "also write numbered PNG frames into this directory")
+var replayScale = flag.Float64("replay-scale", replay.DefaultExportOptions.Scale, "replay frame size multiplier")
+var replayFrameRate = flag.Int("replay-fps", replay.DefaultExportOptions.FrameRate, "replay frames per second")
+var replaySeconds = flag.Float64("replay-seconds", replay.DefaultExportOptions.LastSeconds, "export only the last seconds, 0 for the whole life")
+
 func main() {
 	runtime.LockOSThread()
+	flag.Parse()
+	assets.SetSearchPath(*assetsDir, assets.ConfigDir())
+	currentSettings = settings.Load()
+	if *themeName != "" {
+		currentSettings.Theme = *themeName
+	}
+	initModes()
+	initDifficulty()
+	if *verifyCode != "" {
+		os.Exit(verifyDaily(*verifyCode))
+	}
+	for i := 0; i < len(fieldCells); i++ {
+		fieldCells[i] = i
+	}
+	if *screenName != "" {
+		os.Exit(renderScreen(*screenName))
+	}
 
 	defer graphics.Terminate()
 	err := graphics.Init("Snake game", windowWidth, windowHeight)
 	if err != nil {
 		panic(err)
 	}
+	gameTime = gametime.New(glfw.GetTime)
 	graphics.SetResizeWindowCallback(resizeWindowCallback)
 	graphics.SetKeyInputCallback(keyInputCallback)
-
-	// Create and load textures
-	var snakeTexture = graphics.LoadTexture("snake_skin.png")
-	var backgroundTexture = graphics.LoadTexture("background.png")
-	var gameOverTexture = graphics.LoadTexture("game_over.png")
-	var levelTexture0 = graphics.LoadTexture("level_1.png")
-	var levelTexture1 = graphics.LoadTexture("level_2.png")
-	var levelTexture2 = graphics.LoadTexture("level_3.png")
-	var levelTexture3 = graphics.LoadTexture("level_4.png")
-	var finishLevelTexture = graphics.LoadTexture("finish.png")
-	var startGameTexture = graphics.LoadTexture("start_game.png")
-
-	levelTextures := [levelsNumber]uint32{
-		levelTexture0,
-		levelTexture1,
-		levelTexture2,
-		levelTexture3,
-		finishLevelTexture,
+	restoreWindow()
+	defer saveWindow()
+	initAudio()
+	defer closeAudio()
+	if *postEffects != "" {
+		err = graphics.SetPostEffects(strings.Split(*postEffects, ","))
+		if err != nil {
+			panic(err)
+		}
 	}
 
-	for i := 0; i < len(fieldCells); i++ {
-		fieldCells[i] = i
-	}
+	subscribe()
 
-	resetGame(0, 3)
+	resetGame(0, startLength())
 	gameLogic := func() {
+		updateTheme()
+		graphics.SetLetterbox(gameScene.Theme.Manifest.LetterboxColor, gameScene.BoardBackground(gameLevel))
+		frameTime = clock()
+		animations.Update(frameTime)
 		switch {
 		case startGame:
 			showLevel = true
-			drawBackground(startGameTexture)
 		case gameOver:
 			showLevel = true
-			drawBackground(gameOverTexture)
 		case showLevel:
-			if !progressSaved {
-				saveProgress(gameLevel)
-				progressSaved = true
-			}
 			if startLevel {
-				gameLevel = 0
-				timeWindow = getTimeWindow(gameLevel)
+				gameLevel = gameMode.Rules().Level
+				timeWindow = levelTimeWindow(gameLevel)
 				if loadLevel {
-					gameLevel = loadProgress()
-					timeWindow = getTimeWindow(gameLevel)
+					progress := loadProgress()
+					gameLevel = progress.Level
+					carriedEffects = progress.Effects
+					timeWindow = levelTimeWindow(gameLevel)
 					loadLevel = false
 				}
 			}
 			startLevel = false
 			resetLevel = true
-			textureItem := levelTextures[gameLevel]
-			drawBackground(textureItem)
 		case pauseGame:
 			timeToMove = false
-			startTime = glfw.GetTime()
-			drawBackground(backgroundTexture)
-			food.Draw(snakeTexture, drawObject)
-			snake.Draw(snakeTexture, drawObject)
+			startTime = clock()
 		case resetLevel:
 			resetLevel = false
-			resetGame(gameLevel, 3)
+			if !runActive {
+				beginRun()
+			}
+			resetGame(gameLevel, startLength())
+			if carriedEffects != nil {
+				powerUps.RestoreEffects(carriedEffects, startTime)
+				carriedEffects = nil
+			}
+			bus.Publish(events.GameStarted{Level: gameLevel, Time: startTime})
 			fallthrough
 		default:
-			endTime = glfw.GetTime()
+			endTime = clock()
 			period = float32(endTime - startTime)
+			rules := gameMode.Rules()
 
-			if period >= timeWindow {
+			moveWindow := timeWindow
+			if powerUps.Active(powerup.SlowMotion) {
+				moveWindow *= slowMotionFactor
+			}
+			if boosting() && !rules.StepOnKey {
+				moveWindow /= 2
+			}
+			if rules.StepOnKey {
+				// Puzzle steps wait for the direction keys
+				startTime = endTime
+				runTime += float64(period)
+				period = 0
+				timeToMove, stepRequested = stepRequested, false
+			} else if period >= moveWindow {
 				startTime = endTime
 				timeToMove = true
+				runTime += float64(period)
+				updateBoost(float64(period))
 			}
 
 			snakeHead := snake.GetHead()
-			x, y := snakeHead.GetCoords().Elem()
-			frontX, frontY := x, y
-			if horizontalMove {
-				frontX += float32(direction) * float32(period)
-			} else {
-				frontY += float32(direction) * float32(period)
+			var x, y float32
+
+			if !gameOver && rules.TimeLimit > 0 && runTime >= rules.TimeLimit {
+				gameOver = true
+				bus.Publish(events.TimeUp{Time: frameTime})
 			}
-			snake.SetFront(mgl32.Vec2{frontX, frontY})
-
-			if frontX >= higherEdge ||
-				frontX <= lowerEdge ||
-				frontY >= higherEdge ||
-				frontY <= lowerEdge ||
-				snake.CheckIntersection() ||
-				gameLevel == levelsNumber-1 {
+			if gameLevel == finalLevel() {
 				gameOver = true
 			}
 
-			if timeToMove {
-				timeToMove = false
-				if horizontalMove {
-					x += float32(direction)
+			foods.Expire(endTime)
+			fillFood()
+			powerUps.Update(endTime, getPowerUpPeriod(gameLevel), freeCells())
+			hazards.Update(endTime, freeCells())
+
+			moved := false
+			var eatenFood snakemodule.Food
+			if timeToMove && !gameOver {
+				target := nextCell()
+				cause, hit := collisionBoard().Step(target)
+				if hit && cause == events.HitWall && powerUps.Consume(powerup.Shield) {
+					bounceOffWall()
+					bus.Publish(events.ShieldUsed{Position: snakeHead.GetCoords(), Time: frameTime})
+					cause, hit = collisionBoard().Step(nextCell())
+				}
+				if hit {
+					gameOver = true
+					bus.Publish(events.SnakeDied{
+						Cause:    cause,
+						Position: snakeHead.GetCoords(),
+						Level:    gameLevel,
+						Time:     frameTime,
+					})
+				}
+				moved = !hit
+			}
+			timeToMove = false
+			if moved {
+				if gameMode == mode.Daily {
+					recordDailyMove()
+				}
+				x, y = nextCell().Elem()
+				// Whole steps, the front is on the next cell at once
+				snake.SetFront(mgl32.Vec2{x, y})
+				if rules.StepOnKey {
+					puzzleMoves++
+				}
+
+				eatenFood, foodWasEaten = snake.Eat(foods)
+				item, collected := powerUps.Collect(snake.GetFront(), endTime)
+				poison, poisoned := hazards.EatPoison(snake.GetFront(), endTime)
+				if poisoned {
+					snake.Shrink(poison.Kind.Type().Shrink)
+				}
+
+				target := mgl32.Vec2{x, y}
+				exit, rotation, teleported := hazards.Portal(target)
+				if teleported {
+					from := directionVector()
+					to := hazard.Rotate(from, rotation)
+					setDirection(to)
+					snake.MoveVia(exit, from, to)
+					x, y = exit.Elem()
 				} else {
-					y += float32(direction)
+					snake.Move(target)
 				}
 
-				foodWasEaten = snake.Eat(food)
-				snake.Move(mgl32.Vec2{x, y})
+				if collected {
+					bus.Publish(events.PowerUpCollected{Kind: item.Kind, Position: item.Position, Time: frameTime})
+				}
+				if poisoned {
+					bus.Publish(events.PoisonEaten{Kind: poison.Kind, Position: poison.Position, Time: frameTime})
+				}
+				if teleported {
+					bus.Publish(events.Teleported{From: target, To: exit, Time: frameTime})
+				}
+				if powerUps.Active(powerup.Magnet) {
+					foods.Pull(mgl32.Vec2{x, y}, magnetRadius, freeCells())
+				}
+				foods.Step(mgl32.Vec2{x, y}, freeCells())
+				moveRivals()
+				if !rules.StepOnKey {
+					timeWindow = rampTimeWindow(baseTimeWindow())
+				}
 			}
 
 			if foodWasEaten {
 				foodWasEaten = false
-				eatenFoodCounter += 1
-				if eatenFoodCounter == getFoodLimit(gameLevel) {
+				eatenFoodCounter += eatenFood.Kind.Type().Points
+				runPoints += eatenFood.Kind.Type().Points
+				bus.Publish(events.FoodEaten{
+					Position: eatenFood.GetCoords(),
+					Kind:     eatenFood.Kind,
+					Count:    eatenFoodCounter,
+					Time:     frameTime,
+				})
+				if rules.LevelUps && eatenFoodCounter >= getFoodLimit(gameLevel) {
 					gameLevel += 1
-					timeWindow = getTimeWindow(gameLevel)
-					progressSaved = false
-					showLevel = true
+					if rules.NoLevelCap {
+						// The board stays, only the speed and the food change
+						eatenFoodCounter = 0
+						timeWindow = rampTimeWindow(baseTimeWindow())
+						fillFood()
+					} else {
+						timeWindow = levelTimeWindow(gameLevel)
+						showLevel = true
+					}
+					bus.Publish(events.LevelCompleted{
+						Level:     gameLevel - 1,
+						NextLevel: gameLevel,
+						Time:      frameTime,
+					})
 				} else {
-					setFoodPosition(fieldCells)
+					fillFood()
 				}
 			}
 
-			drawBackground(backgroundTexture)
+			if moved && rules.StepOnKey && !showLevel && !gameOver && outOfMoves() {
+				gameOver = true
+				bus.Publish(events.OutOfMoves{Time: frameTime})
+			}
 
-			if period < (2*timeWindow/7) || period > (5*timeWindow/7) {
-				food.Draw(snakeTexture, drawObject)
+			if moved {
+				bus.Publish(events.SnakeMoved{Head: mgl32.Vec2{x, y}, Time: endTime})
 			}
-			snake.Draw(snakeTexture, drawObject)
 		}
+		drawFrame(gameScene)
 	}
 
 	graphics.MainLoop(gameLogic)
 }
 
-func drawObject(texture uint32, vec mgl32.Vec2) {
-	scaleFactor := float32(2.0 / cellsNumber)
-	scale := mgl32.Scale3D(scaleFactor, scaleFactor, 1)
-	xPos := vec.X()*scaleFactor - 1
-	yPos := vec.Y()*scaleFactor - 1
-	translate := mgl32.Translate3D(xPos, yPos, 0)
-	transform := translate.Mul4(scale)
-	graphics.Draw(texture, transform)
-}
-
-func drawBackground(texture uint32) {
-	scale := mgl32.Scale3D(2, 2, 1)
-	translate := mgl32.Translate3D(-1, -1, 0)
-	transform := translate.Mul4(scale)
-	graphics.Draw(texture, transform)
+// drawFrame draws the current game state, it doesn't change it
+func drawFrame(sc *scene.Scene) {
+	switch {
+	case startGame:
+		sc.DrawBackground(sc.StartGame)
+		if gameMode == mode.Daily {
+			sc.DrawDailySelect(dailyChallenge.Date, dailyRecords[dailyChallenge.Date])
+		} else {
+			sc.DrawModeSelect(gameMode, leaderboard)
+		}
+		if !gameMode.Rules().FixedDifficulty {
+			sc.DrawDifficultySelect(gameDifficulty.Settings().Title, currentSettings.Adaptive)
+		}
+	case gameOver:
+		sc.DrawBackground(sc.GameOver)
+		sc.DrawSummary(runSummary)
+	case showLevel:
+		if gameMode == mode.Puzzle {
+			sc.DrawBackground(sc.BoardBackground(gameLevel))
+			sc.DrawBanner(puzzleBanner(gameLevel))
+		} else if gameMode.Rules().Generated {
+			// The level pictures run out, generated levels go on
+			sc.DrawBackground(sc.BoardBackground(gameLevel))
+			sc.DrawBanner(fmt.Sprintf("LEVEL %d", gameLevel+1), "PRESS ENTER")
+		} else {
+			sc.DrawBackground(sc.LevelScreen(gameLevel))
+		}
+	default:
+		sc.DrawBackground(sc.BoardBackground(gameLevel))
+		sc.DrawHazards(hazards, frameTime)
+		// Food blinks while the game runs, food about to expire blinks faster
+		blink := period >= (2*timeWindow/7) && period <= (5*timeWindow/7)
+		for _, item := range foods.Items {
+			expiring := item.ExpiresAt != 0 && item.ExpiresAt-frameTime < foodExpiryWarning
+			if pauseGame || !(blink || expiring && math.Mod(frameTime, 0.2) < 0.1) {
+				sc.DrawFoodItem(item)
+			}
+		}
+		sc.DrawPowerUps(powerUps)
+		for _, r := range rivals.Rivals {
+			sc.DrawSnakeTinted(r.Snake, scene.RivalTint)
+		}
+		if powerUps.Active(powerup.Ghost) {
+			sc.DrawSnakeTinted(snake, scene.GhostTint)
+		} else {
+			sc.DrawSnake(snake)
+		}
+		sc.DrawHUD(powerUps, hazards, frameTime)
+		if gameMode == mode.Puzzle {
+			sc.DrawStatus(puzzleStatus())
+		} else {
+			sc.DrawRunStatus(gameMode, runScore(), runTime)
+			sc.DrawBoostMeter(boostMeter, boosting())
+		}
+	}
+	sc.DrawAnimations(animations, frameTime)
 }
 
 func keyInputCallback(key graphics.KeyValue, action graphics.KeyAction) {
+	if hazards.Reversed(clock()) {
+		key = reversedKey(key)
+	}
 	if !pauseGame && !gameOver && !showLevel {
 		if (key == graphics.KeyW || key == graphics.KeyUp) && action == graphics.Press {
 			if !horizontalMove && direction == -1 {
 				return
 			}
-			direction = 1
-			horizontalMove = false
+			turn(1, false)
+			stepRequested = true
 		}
 		if (key == graphics.KeyS || key == graphics.KeyDown) && action == graphics.Press {
 			if !horizontalMove && direction == 1 {
 				return
 			}
-			direction = -1
-			horizontalMove = false
+			turn(-1, false)
+			stepRequested = true
 		}
 		if (key == graphics.KeyA || key == graphics.KeyLeft) && action == graphics.Press {
 			if horizontalMove && direction == 1 {
 				return
 			}
-			direction = -1
-			horizontalMove = true
+			turn(-1, true)
+			stepRequested = true
 		}
 		if (key == graphics.KeyD || key == graphics.KeyRight) && action == graphics.Press {
 			if horizontalMove && direction == -1 {
 				return
 			}
-			direction = 1
-			horizontalMove = true
+			turn(1, true)
+			stepRequested = true
 		}
 	}
 
+	// Held keys send repeats until they are let go
+	if key == graphics.KeyLeftShift || key == graphics.KeyRightShift {
+		boostHeld = action != graphics.Release
+	}
+
 	if showLevel && !startGame {
 		if key == graphics.KeyEnter && action == graphics.Press {
 			showLevel = false
@@ -258,6 +474,12 @@ func keyInputCallback(key graphics.KeyValue, action graphics.KeyAction) {
 	}
 
 	if gameOver && !startGame {
+		if key == graphics.KeyG && action == graphics.Press {
+			exportReplay()
+		}
+		if key == graphics.KeyC && action == graphics.Press && gameMode == mode.Daily {
+			copyDailyCode()
+		}
 		if key == graphics.KeyR && action == graphics.Press {
 			gameOver = false
 			startLevel = true
@@ -270,20 +492,143 @@ func keyInputCallback(key graphics.KeyValue, action graphics.KeyAction) {
 	if !gameOver && !showLevel {
 		if key == graphics.KeySpace && action == graphics.Press {
 			pauseGame = !pauseGame
+			gameTime.SetPaused(pauseGame)
+			bus.Publish(events.Paused{Paused: pauseGame, Time: clock()})
 		}
 	}
 
+	if key == graphics.KeyF12 && action == graphics.Press {
+		takeScreenshot()
+	}
+
+	if key == graphics.KeyF11 && action == graphics.Press {
+		cycleWindowMode()
+	}
+
+	if key == graphics.KeyF10 && action == graphics.Press {
+		nextMonitor()
+	}
+
+	if key == graphics.KeyT && action == graphics.Press {
+		nextTheme()
+	}
+
+	if key == graphics.KeyM && action == graphics.Press {
+		toggleMute()
+	}
+
+	if key == graphics.KeyF3 && action == graphics.Press && *debug {
+		stats := graphics.LastFrameStats()
+		log.Printf("draw calls: %d, quads: %d", stats.DrawCalls, stats.Quads)
+	}
+
 	if startGame {
 		if key == graphics.KeyEnter && action == graphics.Press {
 			startGame = false
 		}
-		if key == graphics.KeyL && action == graphics.Press {
+		if (key == graphics.KeyA || key == graphics.KeyLeft) && action == graphics.Press {
+			selectMode(-1)
+		}
+		if (key == graphics.KeyD || key == graphics.KeyRight) && action == graphics.Press {
+			selectMode(1)
+		}
+		if !gameMode.Rules().FixedDifficulty {
+			if (key == graphics.KeyW || key == graphics.KeyUp) && action == graphics.Press {
+				selectDifficulty(1)
+			}
+			if (key == graphics.KeyS || key == graphics.KeyDown) && action == graphics.Press {
+				selectDifficulty(-1)
+			}
+			if key == graphics.KeyX && action == graphics.Press {
+				toggleAdaptive()
+			}
+		}
+		// Saved progress is for the campaign levels
+		if key == graphics.KeyL && action == graphics.Press && gameMode == mode.Campaign {
 			startGame = false
 			loadLevel = true
 		}
 	}
 }
 
+// exportReplay renders the recording in the background
+func exportReplay() {
+	rec := recording.Copy()
+	options := replay.ExportOptions{
+		Scale:       *replayScale,
+		FrameRate:   *replayFrameRate,
+		LastSeconds: *replaySeconds,
+		Theme:       loadedTheme,
+	}
+	go func() {
+		err := replay.ExportGIF(rec, *replayPath, options)
+		if err != nil {
+			fmt.Println("replay export failed:", err)
+			return
+		}
+		if *replayFramesDir != "" {
+			err = replay.ExportPNGFrames(rec, *replayFramesDir, options)
+			if err != nil {
+				fmt.Println("replay export failed:", err)
+				return
+			}
+		}
+		fmt.Println("replay saved to", *replayPath)
+	}()
+}
+
+// turn sets the movement direction, announcing real changes
+func turn(newDirection int8, horizontal bool) {
+	changed := newDirection != direction || horizontal != horizontalMove
+	direction = newDirection
+	horizontalMove = horizontal
+	if !changed {
+		return
+	}
+	bus.Publish(events.DirectionChanged{Direction: directionVector(), Time: clock()})
+}
+
+// directionVector returns the movement direction as a unit vector
+func directionVector() mgl32.Vec2 {
+	if horizontalMove {
+		return mgl32.Vec2{float32(direction), 0}
+	}
+	return mgl32.Vec2{0, float32(direction)}
+}
+
+// setDirection sets the movement direction from a unit vector
+func setDirection(vec mgl32.Vec2) {
+	horizontalMove = vec.X() != 0
+	if horizontalMove {
+		direction = int8(vec.X())
+	} else {
+		direction = int8(vec.Y())
+	}
+}
+
+// reversedKey swaps the movement keys while the controls are reversed
+func reversedKey(key graphics.KeyValue) graphics.KeyValue {
+	switch key {
+	case graphics.KeyW:
+		return graphics.KeyS
+	case graphics.KeyS:
+		return graphics.KeyW
+	case graphics.KeyA:
+		return graphics.KeyD
+	case graphics.KeyD:
+		return graphics.KeyA
+	case graphics.KeyUp:
+		return graphics.KeyDown
+	case graphics.KeyDown:
+		return graphics.KeyUp
+	case graphics.KeyLeft:
+		return graphics.KeyRight
+	case graphics.KeyRight:
+		return graphics.KeyLeft
+	}
+	return key
+}
+
 func resizeWindowCallback(width, height int) (startX, startY, newWidth, newHeight int32) {
 	length := int32(math.Min(float64(width), float64(height)))
 	startX = int32((width - int(length)) / 2)
@@ -299,48 +644,258 @@ func resetGame(level int, snakeLength int) {
 	horizontalMove = true
 	gameLevel = level
 
-	intersectionThreshold = 1 - timeWindow
-	higherEdge = float32(cellsNumber-1) + timeWindow
-	lowerEdge = float32(0) - timeWindow
-
-	snake = snakemodule.InitSnake(snakeLength, intersectionThreshold)
-	setFoodPosition(fieldCells)
+	snake = snakemodule.InitSnake(snakeLength)
+	stepRequested = false
+	boostMeter = 1
 	eatenFoodCounter = 0
+	if gameMode == mode.Daily {
+		resetDailyBoard()
+	} else if gameMode == mode.Puzzle {
+		resetPuzzleBoard(level)
+		fillFood()
+	} else {
+		rivals.Reset(getRivals(level), rivalStarts)
+		hazards.Reset(getHazards(level), clock())
+		foods.Clear()
+		fillFood()
+		powerUps.Clear(clock(), getPowerUpPeriod(level))
+	}
 
-	startTime = glfw.GetTime()
+	startTime = clock()
+}
+
+// fillFood tops the board up to the level food count
+func fillFood() {
+	if len(foods.Items) >= getFoodCount(gameLevel) {
+		return
+	}
+	if gameMode == mode.Daily {
+		foods.Fill(1, daily.Weights, freeCells(), clock())
+		return
+	}
+	if gameMode == mode.Puzzle {
+		placePuzzleFood()
+		return
+	}
+	foods.Fill(getFoodCount(gameLevel), getFoodWeights(gameLevel), freeCells(), clock())
 }
 
-func setFoodPosition(fieldCells []int) {
+// freeCells returns the cells without the snakes, food, power-ups or hazards
+func freeCells() []int {
 	possibleCells := snakemodule.GetPossibleCells(snake, fieldCells)
-	food.SetPosition(possibleCells)
+	possibleCells = helpers.CellsDifference(possibleCells, rivals.Indices())
+	possibleCells = helpers.CellsDifference(possibleCells, foods.Indices())
+	possibleCells = helpers.CellsDifference(possibleCells, powerUps.Indices())
+	return helpers.CellsDifference(possibleCells, hazards.Indices())
+}
+
+// moveRivals steps every rival towards the food,
+// rivals running into anything but food crash
+func moveRivals() {
+	food := foods.Indices()
+	for _, r := range append([]*rival.Rival(nil), rivals.Rivals...) {
+		free := make(map[int]bool)
+		for _, cell := range freeCells() {
+			free[cell] = true
+		}
+		for _, cell := range food {
+			free[cell] = true
+		}
+		target := rivals.Next(r, free, food)
+		x, y := int(target.X()), int(target.Y())
+		if !helpers.InField(x, y) || !free[helpers.CoordsToIndex(x, y)] {
+			rivals.Remove(r)
+			head := r.Snake.GetHead()
+			bus.Publish(events.RivalDied{Position: head.GetCoords(), Time: frameTime})
+			continue
+		}
+		r.Snake.SetFront(target)
+		r.Snake.Eat(foods)
+		r.Snake.Move(target)
+		food = foods.Indices()
+	}
+}
+
+// nextCell is where the head goes on the next step
+func nextCell() mgl32.Vec2 {
+	head := snake.GetHead()
+	return head.GetCoords().Add(directionVector())
 }
 
-func saveProgress(level int) {
-	currentLevel := fmt.Sprintf("%d", level)
-	err := os.WriteFile("progress.txt", []byte(currentLevel), 0644)
+// collisionBoard is what the head can run into right now
+func collisionBoard() collision.Board {
+	return collision.Board{
+		Snake:   snake,
+		Hazards: hazards,
+		Rivals:  rivals.Rivals,
+		Ghost:   powerUps.Active(powerup.Ghost),
+		Now:     frameTime,
+	}
+}
+
+// bounceOffWall turns the snake away from the wall it is about to hit,
+// towards the middle of the board
+func bounceOffWall() {
+	head := snake.GetHead()
+	x, y := head.GetCoords().Elem()
+	center := float32(cellsNumber-1) / 2
+	if horizontalMove {
+		if y < center {
+			turn(1, false)
+		} else {
+			turn(-1, false)
+		}
+	} else {
+		if x < center {
+			turn(1, true)
+		} else {
+			turn(-1, true)
+		}
+	}
+	snake.SetFront(head.GetCoords())
+}
+
+// savedProgress is the campaign level to go on from, with the power-up
+// effects still running when the level before it was finished
+type savedProgress struct {
+	Level   int              `json:"level"`
+	Effects []powerup.Effect `json:"effects,omitempty"`
+}
+
+func saveProgress(level int, effects []powerup.Effect) {
+	data, err := json.Marshal(savedProgress{Level: level, Effects: effects})
+	if err != nil {
+		panic(err)
+	}
+	err = os.WriteFile("progress.txt", data, 0644)
 	if err != nil {
 		panic(err)
 	}
 }
 
-func loadProgress() int {
-	levelStr, err := os.ReadFile("progress.txt")
+// loadProgress also reads the older saves holding only the level number
+func loadProgress() savedProgress {
+	data, err := os.ReadFile("progress.txt")
 	if err != nil {
 		panic(err)
 	}
-	level, err := strconv.Atoi(string(levelStr))
+	var progress savedProgress
+	if json.Unmarshal(data, &progress) == nil {
+		return progress
+	}
+	level, err := strconv.Atoi(strings.TrimSpace(string(data)))
 	if err != nil {
 		panic(err)
 	}
-	return level
+	return savedProgress{Level: level}
 }
 
 func getTimeWindow(level int) float32 {
-	res := float32(0.5) - float32(level)/10
-	return res
+	return currentDifficulty().TimeWindow(level)
+}
+
+// Hazards by level, later levels use the last one
+var levelHazards = []hazard.Level{
+	{},
+	{PoisonPeriod: 10},
+	{
+		Spikes: []hazard.Spike{
+			{Position: mgl32.Vec2{4, 4}, Period: 4, On: 1.5},
+			{Position: mgl32.Vec2{5, 5}, Period: 4, On: 1.5, Offset: 2},
+		},
+		PoisonPeriod: 9,
+	},
+	{
+		Spikes: []hazard.Spike{
+			{Position: mgl32.Vec2{4, 6}, Period: 3, On: 1},
+			{Position: mgl32.Vec2{6, 4}, Period: 3, On: 1, Offset: 1.5},
+		},
+		Portals: []hazard.Portal{
+			{A: mgl32.Vec2{1, 8}, B: mgl32.Vec2{8, 1}},
+		},
+		PoisonPeriod: 8,
+	},
+	{
+		Spikes: []hazard.Spike{
+			{Position: mgl32.Vec2{4, 4}, Period: 3, On: 1},
+			{Position: mgl32.Vec2{5, 5}, Period: 3, On: 1, Offset: 1},
+			{Position: mgl32.Vec2{4, 5}, Period: 3, On: 1, Offset: 2},
+		},
+		Portals: []hazard.Portal{
+			{A: mgl32.Vec2{2, 2}, B: mgl32.Vec2{7, 7}, Rotation: 1},
+			{A: mgl32.Vec2{2, 7}, B: mgl32.Vec2{7, 2}},
+		},
+		PoisonPeriod: 6,
+	},
+}
+
+func getHazards(level int) hazard.Level {
+	if gameMode.Rules().Generated {
+		return getGeneratedHazards(level)
+	}
+	if level >= len(levelHazards) {
+		level = len(levelHazards) - 1
+	}
+	return levelHazards[level]
+}
+
+// Seconds between power-up spawns, the daily board has none
+func getPowerUpPeriod(level int) float64 {
+	if gameMode == mode.Daily || gameMode == mode.Puzzle {
+		return 0
+	}
+	return math.Max(6, 15-2*float64(level))
+}
+
+// Food lying on the board at once
+func getFoodCount(level int) int {
+	return 1 + level/2
+}
+
+// Where the rivals come in, the board corners away from the player
+var rivalStarts = []rival.Start{
+	{Cells: []mgl32.Vec2{{9, 9}, {8, 9}, {7, 9}}, Direction: mgl32.Vec2{-1, 0}},
+	{Cells: []mgl32.Vec2{{0, 5}, {0, 6}, {0, 7}}, Direction: mgl32.Vec2{0, 1}},
+}
+
+// Rivals by level, later levels use the last one
+var levelRivals = []rival.Difficulty{
+	{},
+	{},
+	{Count: 1, ReactionDelay: 2, Lookahead: 2},
+	{Count: 1, ReactionDelay: 1, Lookahead: 4},
+	{Count: 2, ReactionDelay: 0, Lookahead: 6},
+}
+
+func getRivals(level int) rival.Difficulty {
+	// Their starts may be walled in on generated levels
+	if gameMode.Rules().Generated {
+		return rival.Difficulty{}
+	}
+	if level >= len(levelRivals) {
+		level = len(levelRivals) - 1
+	}
+	return levelRivals[level]
+}
+
+// Spawn weights of the food kinds by level, later levels use the last one
+var foodWeights = []snakemodule.FoodWeights{
+	{snakemodule.NormalFood: 1},
+	{snakemodule.NormalFood: 6, snakemodule.BonusFood: 2, snakemodule.ShrinkFood: 1},
+	{snakemodule.NormalFood: 6, snakemodule.BonusFood: 2, snakemodule.GoldenFood: 1, snakemodule.ShrinkFood: 1, snakemodule.PreyFood: 1},
+	{snakemodule.NormalFood: 4, snakemodule.BonusFood: 2, snakemodule.GoldenFood: 2, snakemodule.ShrinkFood: 2, snakemodule.PreyFood: 2},
+}
+
+func getFoodWeights(level int) snakemodule.FoodWeights {
+	if level >= len(foodWeights) {
+		level = len(foodWeights) - 1
+	}
+	return foodWeights[level]
 }
 
 func getFoodLimit(level int) int {
-	limit := 15 + 5*level
-	return limit
+	if gameMode == mode.Puzzle {
+		return len(puzzleBoard.Food)
+	}
+	return currentDifficulty().FoodLimit(level)
 }
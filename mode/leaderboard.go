@@ -0,0 +1,97 @@
+package mode
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Runs kept per mode
+const MaxEntries = 10
+
+type Entry struct {
+	Score   int       `json:"score"`
+	Level   int       `json:"level"`
+	Length  int       `json:"length"`
+	Seconds float64   `json:"seconds"`
+	Date    time.Time `json:"date"`
+	// Preset the run was played on
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// Leaderboard holds the best runs of every mode, best first
+type Leaderboard map[string][]Entry
+
+// Path of the leaderboard file in the user config dir
+func Path() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "leaderboard.json"
+	}
+	return filepath.Join(dir, "snakegame", "leaderboard.json")
+}
+
+// LoadLeaderboard returns the saved runs, or an empty leaderboard
+func LoadLeaderboard() Leaderboard {
+	board := Leaderboard{}
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("warning: %v, starting a new leaderboard", err)
+		}
+		return board
+	}
+	err = json.Unmarshal(data, &board)
+	if err != nil {
+		log.Printf("warning: leaderboard %s: %v, starting a new leaderboard", Path(), err)
+		return Leaderboard{}
+	}
+	return board
+}
+
+func (board Leaderboard) Save() error {
+	data, err := json.MarshalIndent(board, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(Path()), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(), data, 0644)
+}
+
+// Best returns the top score of the mode, 0 without runs
+func (board Leaderboard) Best(mode Mode) int {
+	entries := board[mode.String()]
+	if len(entries) == 0 {
+		return 0
+	}
+	return entries[0].Score
+}
+
+// Add puts the run on the mode board and returns its place,
+// 0 when it isn't among the best MaxEntries
+func (board Leaderboard) Add(mode Mode, entry Entry) int {
+	entries := board[mode.String()]
+	// Ties go below the earlier runs
+	rank := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Score < entry.Score
+	})
+	if rank >= MaxEntries {
+		return 0
+	}
+	entries = append(entries, Entry{})
+	copy(entries[rank+1:], entries[rank:])
+	entries[rank] = entry
+	if len(entries) > MaxEntries {
+		entries = entries[:MaxEntries]
+	}
+	board[mode.String()] = entries
+	return rank + 1
+}
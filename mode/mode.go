@@ -0,0 +1,103 @@
+package mode
+
+type Mode int
+
+const (
+	// The fixed levels, one after the other
+	Campaign Mode = iota
+	// Eat as much as possible before the clock runs out
+	TimeAttack60
+	TimeAttack120
+	// Stay alive while the speed keeps going up
+	Survival
+	// Levels go on and the board fills up with snake
+	Endless
+	// Every level is a new generated wall layout
+	Generated
+	// The same board for everyone on the day
+	Daily
+	// Hand-made boards solved one step per key press
+	Puzzle
+	Count
+)
+
+// Rules say how a mode is played and scored
+type Rules struct {
+	// Leaderboard key
+	Name string
+	// Shown on the start screen
+	Title string
+	// Seconds a run lasts, 0 for no limit
+	TimeLimit float64
+	// Level the run starts on, its hazards and rivals stay for the run
+	Level int
+	// Levels are completed by eating the level food limit
+	LevelUps bool
+	// The run goes past the last campaign level without a new board
+	NoLevelCap bool
+	// Levels get generated walls and go on past the campaign ones
+	Generated bool
+	// Speed goes up with the run time rather than with the level
+	SpeedRamp bool
+	// Score is the seconds survived rather than the food points
+	TimeScore bool
+	// The snake waits for a direction key before each step
+	StepOnKey bool
+	// Everyone plays the same rules, difficulty presets don't apply
+	FixedDifficulty bool
+}
+
+var modeRules = [Count]Rules{
+	Campaign:      {Name: "campaign", Title: "CAMPAIGN", LevelUps: true},
+	TimeAttack60:  {Name: "time60", Title: "TIME ATTACK 60", TimeLimit: 60, Level: 1},
+	TimeAttack120: {Name: "time120", Title: "TIME ATTACK 120", TimeLimit: 120, Level: 1},
+	Survival:      {Name: "survival", Title: "SURVIVAL", Level: 2, SpeedRamp: true, TimeScore: true},
+	Endless:       {Name: "endless", Title: "ENDLESS", LevelUps: true, NoLevelCap: true},
+	Generated:     {Name: "generated", Title: "GENERATED", LevelUps: true, Generated: true},
+	Daily:         {Name: "daily", Title: "DAILY CHALLENGE", FixedDifficulty: true},
+	Puzzle:        {Name: "puzzle", Title: "PUZZLES", LevelUps: true, StepOnKey: true, FixedDifficulty: true},
+}
+
+// Endless reports whether there is no last level to finish
+func (rules Rules) Endless() bool {
+	return rules.NoLevelCap || rules.Generated
+}
+
+func (mode Mode) Rules() Rules {
+	return modeRules[mode]
+}
+
+// Leaderboard key of the mode
+func (mode Mode) String() string {
+	return modeRules[mode].Name
+}
+
+// Parse returns the mode named name, or the campaign
+func Parse(name string) Mode {
+	for mode := Campaign; mode < Count; mode++ {
+		if mode.String() == name {
+			return mode
+		}
+	}
+	return Campaign
+}
+
+// Next cycles through the modes, step is 1 or -1
+func (mode Mode) Next(step int) Mode {
+	return Mode((int(mode) + step + int(Count)) % int(Count))
+}
+
+// Summary describes a finished run
+type Summary struct {
+	Mode  Mode
+	Score int
+	// Best score of the mode before this run
+	Best int
+	// Place on the leaderboard, 0 when the run didn't make it
+	Rank    int
+	Level   int
+	Length  int
+	Seconds float64
+	// What ended the run: a death cause, "time", "moves" or "finished"
+	Reason string
+}
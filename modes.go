@@ -0,0 +1,164 @@
+package main
+
+import (
+	"log"
+	"math"
+	"snakegame/daily"
+	"snakegame/hazard"
+	"snakegame/levelgen"
+	"snakegame/mode"
+	"snakegame/scene"
+	"time"
+)
+
+// Mode chosen on the start screen, saved in the settings
+var gameMode mode.Mode
+var leaderboard mode.Leaderboard
+
+// The current run, in the campaign it spans the levels played in a row
+var runActive = false
+var runTime float64
+var runPoints int
+
+// Seed of the generated levels of the run
+var generatedSeed int64
+
+// Last finished run, shown on the game over screen
+var runSummary mode.Summary
+
+func initModes() {
+	gameMode = mode.Parse(currentSettings.Mode)
+	leaderboard = mode.LoadLeaderboard()
+	dailyRecords = daily.LoadRecords()
+	loadPuzzles()
+}
+
+// selectMode moves the start screen selection and saves it
+func selectMode(step int) {
+	gameMode = gameMode.Next(step)
+	currentSettings.Mode = gameMode.String()
+	err := currentSettings.Save()
+	if err != nil {
+		log.Printf("warning: settings not saved: %v", err)
+	}
+}
+
+func beginRun() {
+	runActive = true
+	runTime = 0
+	runPoints = 0
+	if gameMode.Rules().SpeedRamp {
+		timeWindow = getSurvivalTimeWindow(0)
+	}
+	if gameMode == mode.Daily {
+		beginDaily()
+	}
+	if gameMode.Rules().Generated {
+		generatedSeed = time.Now().UnixNano()
+	}
+}
+
+// runScore is what the leaderboard ranks the run by
+func runScore() int {
+	if gameMode == mode.Puzzle {
+		// Puzzles solved
+		return gameLevel
+	}
+	if gameMode.Rules().TimeScore {
+		return int(runTime)
+	}
+	return runPoints
+}
+
+// finalLevel is the level that ends the run once reached,
+// -1 in modes that go on
+func finalLevel() int {
+	switch {
+	case gameMode == mode.Puzzle:
+		return len(puzzles)
+	case gameMode.Rules().Endless():
+		return -1
+	}
+	return levelsNumber - 1
+}
+
+// endRun puts the run on the leaderboard and keeps its summary
+func endRun(reason string) {
+	if !runActive {
+		return
+	}
+	runActive = false
+	carriedEffects = nil
+	runSummary = mode.Summary{
+		Mode:    gameMode,
+		Score:   runScore(),
+		Best:    leaderboard.Best(gameMode),
+		Level:   gameLevel,
+		Length:  snake.Length(),
+		Seconds: runTime,
+		Reason:  reason,
+	}
+	if gameMode == mode.Daily {
+		// The daily board keeps its own records by date
+		runSummary.Best, runSummary.Rank = finishDaily(runSummary.Score)
+		return
+	}
+	runSummary.Rank = leaderboard.Add(gameMode, mode.Entry{
+		Score:      runSummary.Score,
+		Level:      runSummary.Level,
+		Length:     runSummary.Length,
+		Seconds:    runSummary.Seconds,
+		Date:       time.Now(),
+		Difficulty: difficultyName(),
+	})
+	if runSummary.Rank > 0 {
+		err := leaderboard.Save()
+		if err != nil {
+			log.Printf("warning: leaderboard not saved: %v", err)
+		}
+	}
+	if *debug {
+		log.Printf("%s: score %s, %s, rank %d", gameMode, scene.FormatScore(gameMode, runSummary.Score), reason, runSummary.Rank)
+	}
+}
+
+// Survival steps get a tenth shorter every 15 seconds
+func getSurvivalTimeWindow(seconds float64) float32 {
+	preset := currentDifficulty()
+	window := math.Max(float64(preset.Floor), float64(preset.Start)*math.Pow(0.9, seconds/15))
+	return tuneTimeWindow(float32(window))
+}
+
+// levelTimeWindow is the step time of the level, modes going past
+// the campaign levels stop speeding up and puzzles keep the first one
+func levelTimeWindow(level int) float32 {
+	if gameMode.Rules().StepOnKey {
+		// Puzzle steps aren't timed, the window only sets the music pace
+		return getTimeWindow(0)
+	}
+	if gameMode.Rules().Endless() && level > levelsNumber-1 {
+		level = levelsNumber - 1
+	}
+	return tuneTimeWindow(getTimeWindow(level))
+}
+
+// Generated levels get more walls as they go
+func getGeneratedHazards(level int) hazard.Level {
+	layout, err := levelgen.Generate(levelgen.Options{
+		Width:       cellsNumber,
+		Height:      cellsNumber,
+		Difficulty:  math.Min(1, 0.2+0.1*float64(level)),
+		SnakeLength: startLength(),
+		FoodQuota:   getFoodLimit(level),
+		Seed:        generatedSeed + int64(level),
+	})
+	if err != nil {
+		log.Printf("warning: level generation: %v", err)
+	}
+	// Poison comes as in the campaign, spikes and portals could sit on walls
+	campaign := levelHazards[len(levelHazards)-1]
+	if level < len(levelHazards) {
+		campaign = levelHazards[level]
+	}
+	return hazard.Level{Walls: layout.Walls, PoisonPeriod: campaign.PoisonPeriod}
+}
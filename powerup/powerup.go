@@ -0,0 +1,205 @@
+package powerup
+
+import (
+	"math/rand"
+	"snakegame/helpers"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Kind int
+
+const (
+	// Slows the snake down
+	SlowMotion Kind = iota
+	// Lets the head pass through the body
+	Ghost
+	// Pulls nearby food towards the head
+	Magnet
+	// Absorbs one wall hit
+	Shield
+	KindCount
+)
+
+// Type describes a power-up kind
+type Type struct {
+	Name string
+	// Seconds the effect lasts, 0 lasts until it is used up
+	Duration float64
+}
+
+var Types = [KindCount]Type{
+	SlowMotion: {Name: "powerup_slow", Duration: 6},
+	Ghost:      {Name: "powerup_ghost", Duration: 5},
+	Magnet:     {Name: "powerup_magnet", Duration: 8},
+	Shield:     {Name: "powerup_shield"},
+}
+
+func (kind Kind) Type() Type {
+	return Types[kind]
+}
+
+// Sprite name of the kind
+func (kind Kind) String() string {
+	return Types[kind].Name
+}
+
+// Seconds a power-up lies on the board before it disappears
+const ItemLifetime = 8
+
+// Item is a power-up waiting on the board
+type Item struct {
+	Kind      Kind       `json:"kind"`
+	Position  mgl32.Vec2 `json:"position"`
+	ExpiresAt float64    `json:"expiresAt"`
+}
+
+// Effect is a collected power-up
+type Effect struct {
+	Kind  Kind    `json:"kind"`
+	Start float64 `json:"start"`
+	// 0 for effects lasting until they are used up
+	End float64 `json:"end"`
+}
+
+// Remaining returns the part of the effect left, 1 for lasting effects
+func (effect Effect) Remaining(now float64) float32 {
+	if effect.End == 0 {
+		return 1
+	}
+	return float32((effect.End - now) / (effect.End - effect.Start))
+}
+
+// State holds the power-ups on the board and the active effects
+type State struct {
+	Items     []Item   `json:"items"`
+	Effects   []Effect `json:"effects"`
+	nextSpawn float64
+	rand      *rand.Rand
+}
+
+func NewState(seed int64) *State {
+	return &State{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Clear removes the items and effects, the next spawn comes after period
+func (state *State) Clear(now, period float64) {
+	state.Items = nil
+	state.Effects = nil
+	state.nextSpawn = now + period
+}
+
+// Update drops expired items and effects and every period spawns
+// a random power-up on one of the free cells
+func (state *State) Update(now, period float64, freeCells []int) {
+	items := state.Items[:0]
+	for _, item := range state.Items {
+		if item.ExpiresAt > now {
+			items = append(items, item)
+		}
+	}
+	state.Items = items
+
+	effects := state.Effects[:0]
+	for _, effect := range state.Effects {
+		if effect.End == 0 || effect.End > now {
+			effects = append(effects, effect)
+		}
+	}
+	state.Effects = effects
+
+	if period <= 0 || now < state.nextSpawn {
+		return
+	}
+	state.nextSpawn = now + period
+	free := helpers.CellsDifference(freeCells, state.Indices())
+	if len(free) == 0 {
+		return
+	}
+	x, y := helpers.IndexToCoords(free[state.rand.Intn(len(free))])
+	state.Items = append(state.Items, Item{
+		Kind:      Kind(state.rand.Intn(int(KindCount))),
+		Position:  mgl32.Vec2{float32(x), float32(y)},
+		ExpiresAt: now + ItemLifetime,
+	})
+}
+
+// Collect activates the item on the cell, picking the same kind again
+// restarts its effect
+func (state *State) Collect(cell mgl32.Vec2, now float64) (Item, bool) {
+	for i, item := range state.Items {
+		if item.Position != cell {
+			continue
+		}
+		state.Items = append(state.Items[:i], state.Items[i+1:]...)
+		state.Consume(item.Kind)
+		effect := Effect{Kind: item.Kind, Start: now}
+		if duration := item.Kind.Type().Duration; duration > 0 {
+			effect.End = now + duration
+		}
+		state.Effects = append(state.Effects, effect)
+		return item, true
+	}
+	return Item{}, false
+}
+
+func (state *State) Active(kind Kind) bool {
+	for _, effect := range state.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Consume ends the effect of the kind, like a shield taking a hit
+func (state *State) Consume(kind Kind) bool {
+	for i, effect := range state.Effects {
+		if effect.Kind == kind {
+			state.Effects = append(state.Effects[:i], state.Effects[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SaveEffects returns the active effects with their times counted
+// from now, so they can be restored later on another clock
+func (state *State) SaveEffects(now float64) []Effect {
+	return shiftEffects(state.Effects, -now)
+}
+
+// RestoreEffects puts effects returned by SaveEffects back,
+// their times counted from now again
+func (state *State) RestoreEffects(effects []Effect, now float64) {
+	state.Effects = shiftEffects(effects, now)
+}
+
+func shiftEffects(effects []Effect, by float64) []Effect {
+	shifted := make([]Effect, len(effects))
+	for i, effect := range effects {
+		effect.Start += by
+		if effect.End != 0 {
+			effect.End += by
+		}
+		shifted[i] = effect
+	}
+	return shifted
+}
+
+// Indices returns the field cells taken by items
+func (state *State) Indices() []int {
+	indices := make([]int, len(state.Items))
+	for i, item := range state.Items {
+		indices[i] = helpers.CoordsToIndex(int(item.Position.X()), int(item.Position.Y()))
+	}
+	return indices
+}
+
+// Copy returns the items and effects, for replays
+func (state *State) Copy() State {
+	return State{
+		Items:   append([]Item(nil), state.Items...),
+		Effects: append([]Effect(nil), state.Effects...),
+	}
+}
@@ -0,0 +1,69 @@
+package powerup
+
+import (
+	"snakegame/gametime"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSaveAndRestoreEffects(t *testing.T) {
+	var state State
+	state.Effects = []Effect{
+		{Kind: SlowMotion, Start: 100, End: 106},
+		{Kind: Shield, Start: 98},
+	}
+	saved := state.SaveEffects(102)
+
+	var restored State
+	restored.RestoreEffects(saved, 10)
+	want := []Effect{
+		{Kind: SlowMotion, Start: 8, End: 14},
+		{Kind: Shield, Start: 6},
+	}
+	if len(restored.Effects) != len(want) {
+		t.Fatalf("effects = %v, want %v", restored.Effects, want)
+	}
+	for i, effect := range restored.Effects {
+		if effect != want[i] {
+			t.Errorf("effect %d = %v, want %v", i, effect, want[i])
+		}
+	}
+	if remaining := restored.Effects[0].Remaining(10); remaining != state.Effects[0].Remaining(102) {
+		t.Errorf("%v of the slow motion left after restoring, want %v", remaining, state.Effects[0].Remaining(102))
+	}
+
+	// Lasting effects stay until they are used up
+	restored.Update(1000, 0, nil)
+	if !restored.Active(Shield) || restored.Active(SlowMotion) {
+		t.Errorf("effects after they ran out: %v", restored.Effects)
+	}
+}
+
+func TestEffectsWaitOutAPause(t *testing.T) {
+	window := 100.0
+	clock := gametime.New(func() float64 { return window })
+	state := NewState(1)
+	state.Items = []Item{{Kind: Magnet, Position: mgl32.Vec2{2, 2}, ExpiresAt: clock.Now() + ItemLifetime}}
+	state.Items = append(state.Items, Item{Kind: SlowMotion, Position: mgl32.Vec2{3, 3}, ExpiresAt: clock.Now() + ItemLifetime})
+	state.Collect(mgl32.Vec2{2, 2}, clock.Now())
+	window += 2
+	remaining := state.Effects[0].Remaining(clock.Now())
+
+	clock.SetPaused(true)
+	window += 1000
+	state.Update(clock.Now(), 0, nil)
+	if !state.Active(Magnet) || len(state.Items) != 1 {
+		t.Fatalf("effects %v and items %v ran out while paused", state.Effects, state.Items)
+	}
+	if got := state.Effects[0].Remaining(clock.Now()); got != remaining {
+		t.Errorf("%v of the magnet left after the pause, want %v", got, remaining)
+	}
+
+	clock.SetPaused(false)
+	window += Magnet.Type().Duration
+	state.Update(clock.Now(), 0, nil)
+	if state.Active(Magnet) {
+		t.Error("the magnet outlasted its duration after the pause")
+	}
+}
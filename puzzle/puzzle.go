@@ -0,0 +1,159 @@
+package puzzle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Goal string
+
+const (
+	// Eat the food in order
+	EatAll Goal = "eat"
+	// Eat the food in order until the snake covers every free cell
+	FillBoard Goal = "fill"
+)
+
+// Puzzle is a hand-made board with the food in a fixed order
+type Puzzle struct {
+	Name string `json:"name"`
+	// Rows from the top: # for walls, . for free cells, 1-9 and then A-Z
+	// for the food in the order it shows up, one at a time
+	Map []string `json:"map"`
+	// Snake cells from tail to head, map coords from the bottom left
+	Snake [][2]int `json:"snake"`
+	Goal  Goal     `json:"goal"`
+	// Moves allowed, 0 for any number
+	MaxMoves int `json:"maxMoves"`
+}
+
+// Board is a puzzle placed in the middle of the game field,
+// the field outside the map is walled off
+type Board struct {
+	Size  int
+	Walls []mgl32.Vec2
+	Food  []mgl32.Vec2
+	Snake []mgl32.Vec2
+}
+
+// Load reads a JSON list of puzzles
+func Load(r io.Reader) ([]Puzzle, error) {
+	var puzzles []Puzzle
+	err := json.NewDecoder(r).Decode(&puzzles)
+	if err != nil {
+		return nil, fmt.Errorf("puzzles: %w", err)
+	}
+	return puzzles, nil
+}
+
+// foodOrder returns the place of a food mark in the sequence, or -1
+func foodOrder(mark byte) int {
+	switch {
+	case mark >= '1' && mark <= '9':
+		return int(mark - '1')
+	case mark >= 'A' && mark <= 'Z':
+		return 9 + int(mark-'A')
+	}
+	return -1
+}
+
+// Board checks the puzzle and places it on a field of size cells a side
+func (puzzle Puzzle) Board(size int) (Board, error) {
+	height := len(puzzle.Map)
+	if height == 0 || height > size {
+		return Board{}, fmt.Errorf("puzzle %q: the map needs 1 to %d rows", puzzle.Name, size)
+	}
+	width := len(puzzle.Map[0])
+	if width == 0 || width > size {
+		return Board{}, fmt.Errorf("puzzle %q: the map needs 1 to %d columns", puzzle.Name, size)
+	}
+	offsetX, offsetY := (size-width)/2, (size-height)/2
+
+	board := Board{Size: size}
+	free := make(map[[2]int]bool)
+	var food []mgl32.Vec2
+	var found []bool
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			mapX, mapY := x-offsetX, height-1-(y-offsetY)
+			mark := byte('#')
+			if mapX >= 0 && mapX < width && mapY >= 0 && mapY < height {
+				row := puzzle.Map[mapY]
+				if len(row) != width {
+					return Board{}, fmt.Errorf("puzzle %q: map rows differ in length", puzzle.Name)
+				}
+				mark = row[mapX]
+			}
+			cell := mgl32.Vec2{float32(x), float32(y)}
+			if mark == '#' {
+				board.Walls = append(board.Walls, cell)
+				continue
+			}
+			free[[2]int{x, y}] = true
+			if order := foodOrder(mark); order >= 0 {
+				for len(food) <= order {
+					food = append(food, mgl32.Vec2{})
+					found = append(found, false)
+				}
+				if found[order] {
+					return Board{}, fmt.Errorf("puzzle %q: food %c is on the map twice", puzzle.Name, mark)
+				}
+				food[order], found[order] = cell, true
+			} else if mark != '.' {
+				return Board{}, fmt.Errorf("puzzle %q: unknown map mark %q", puzzle.Name, mark)
+			}
+		}
+	}
+	for i, ok := range found {
+		if !ok {
+			return Board{}, fmt.Errorf("puzzle %q: food %d is missing", puzzle.Name, i+1)
+		}
+	}
+	board.Food = food
+
+	if len(puzzle.Snake) < 2 {
+		return Board{}, fmt.Errorf("puzzle %q: the snake needs a head and a tail", puzzle.Name)
+	}
+	for i, cell := range puzzle.Snake {
+		x, y := cell[0]+offsetX, cell[1]+offsetY
+		if !free[[2]int{x, y}] {
+			return Board{}, fmt.Errorf("puzzle %q: snake cell %v is not free", puzzle.Name, cell)
+		}
+		if i > 0 {
+			previous := puzzle.Snake[i-1]
+			if abs(cell[0]-previous[0])+abs(cell[1]-previous[1]) != 1 {
+				return Board{}, fmt.Errorf("puzzle %q: snake cells %v and %v aren't neighbours", puzzle.Name, previous, cell)
+			}
+		}
+		board.Snake = append(board.Snake, mgl32.Vec2{float32(x), float32(y)})
+	}
+
+	if puzzle.Goal == FillBoard && len(board.Snake)+len(board.Food) != len(free) {
+		return Board{}, fmt.Errorf("puzzle %q: %d snake cells and %d food don't fill %d free cells",
+			puzzle.Name, len(board.Snake), len(board.Food), len(free))
+	}
+	return board, nil
+}
+
+// Describe tells the player what the puzzle asks for
+func (puzzle Puzzle) Describe() string {
+	text := "EAT ALL THE FOOD"
+	if puzzle.Goal == FillBoard {
+		text = "FILL THE BOARD"
+	}
+	if puzzle.MaxMoves > 0 {
+		text += fmt.Sprintf(" IN %d MOVES", puzzle.MaxMoves)
+	}
+	return strings.TrimSpace(text)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
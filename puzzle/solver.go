@@ -0,0 +1,122 @@
+package puzzle
+
+import (
+	"snakegame/snakemodule"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Solution is a shortest way through a puzzle
+type Solution struct {
+	Moves int
+	// One of U, D, L and R per move
+	Path string
+}
+
+var steps = [4]struct {
+	dx, dy int
+	name   byte
+}{{0, 1, 'U'}, {0, -1, 'D'}, {-1, 0, 'L'}, {1, 0, 'R'}}
+
+// state is the snake cells from tail to head, the food eaten
+// follows from its length
+type state struct {
+	body   []int
+	parent int
+	move   byte
+}
+
+// Solve searches breadth first for the fewest moves eating all the food,
+// trying up to limit moves. The body blocks the head as in the game,
+// see snakemodule.Snake.Blocked.
+func Solve(board Board, limit int) (Solution, bool) {
+	size := board.Size
+	wall := make([]bool, size*size)
+	for _, cell := range board.Walls {
+		wall[int(cell.Y())*size+int(cell.X())] = true
+	}
+	food := make([]int, len(board.Food))
+	for i, cell := range board.Food {
+		food[i] = int(cell.Y())*size + int(cell.X())
+	}
+	start := make([]int, len(board.Snake))
+	for i, cell := range board.Snake {
+		start[i] = int(cell.Y())*size + int(cell.X())
+	}
+	startLength := len(start)
+
+	if len(food) == 0 {
+		return Solution{}, true
+	}
+	states := []state{{body: start, parent: -1}}
+	seen := map[string]bool{key(start): true}
+	level := []int{0}
+	for depth := 0; len(level) > 0 && depth < limit; depth++ {
+		var next []int
+		for _, i := range level {
+			body := states[i].body
+			eaten := len(body) - startLength
+			head := body[len(body)-1]
+			x, y := head%size, head/size
+			snake := snakemodule.NewSnake(coords(body, size))
+			for _, step := range steps {
+				nx, ny := x+step.dx, y+step.dy
+				if nx < 0 || nx >= size || ny < 0 || ny >= size {
+					continue
+				}
+				target := ny*size + nx
+				// Food is eaten on the step onto it, the snake
+				// isn't growing yet when the step is checked
+				if wall[target] || snake.Blocked(mgl32.Vec2{float32(nx), float32(ny)}) {
+					continue
+				}
+				var moved []int
+				if target == food[eaten] {
+					moved = append(append(moved, body...), target)
+				} else {
+					moved = append(append(moved, body[1:]...), target)
+				}
+				k := key(moved)
+				if seen[k] {
+					continue
+				}
+				seen[k] = true
+				states = append(states, state{body: moved, parent: i, move: step.name})
+				if len(moved)-startLength == len(food) {
+					return solution(states, len(states)-1), true
+				}
+				next = append(next, len(states)-1)
+			}
+		}
+		level = next
+	}
+	return Solution{}, false
+}
+
+func solution(states []state, i int) Solution {
+	var path []byte
+	for ; states[i].parent >= 0; i = states[i].parent {
+		path = append(path, states[i].move)
+	}
+	for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+		path[a], path[b] = path[b], path[a]
+	}
+	return Solution{Moves: len(path), Path: string(path)}
+}
+
+// coords turns board indices back into cells
+func coords(indices []int, size int) []mgl32.Vec2 {
+	cells := make([]mgl32.Vec2, len(indices))
+	for i, index := range indices {
+		cells[i] = mgl32.Vec2{float32(index % size), float32(index / size)}
+	}
+	return cells
+}
+
+func key(body []int) string {
+	bytes := make([]byte, 0, len(body)*2)
+	for _, cell := range body {
+		bytes = append(bytes, byte(cell), byte(cell>>8))
+	}
+	return string(bytes)
+}
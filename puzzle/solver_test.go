@@ -0,0 +1,113 @@
+package puzzle
+
+import (
+	"snakegame/assets"
+	"snakegame/helpers"
+	"snakegame/snakemodule"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestShippedPuzzlesSolve(t *testing.T) {
+	f, err := assets.Open("puzzles/puzzles.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	puzzles, err := Load(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range puzzles {
+		t.Run(p.Name, func(t *testing.T) {
+			board, err := p.Board(helpers.FieldSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			limit := p.MaxMoves
+			if limit == 0 {
+				limit = 200
+			}
+			solution, ok := Solve(board, limit)
+			if !ok {
+				t.Fatalf("no solution within %d moves", limit)
+			}
+			replay(t, board, solution.Path)
+		})
+	}
+}
+
+// replay plays the path on a game snake, every step has to be
+// one the game allows and the food has to be gone at the end
+func replay(t *testing.T, board Board, path string) {
+	t.Helper()
+	walls := make(map[mgl32.Vec2]bool)
+	for _, wall := range board.Walls {
+		walls[wall] = true
+	}
+	snake := snakemodule.NewSnake(board.Snake)
+	eaten := 0
+	for i := range path {
+		step := map[byte]mgl32.Vec2{'U': {0, 1}, 'D': {0, -1}, 'L': {-1, 0}, 'R': {1, 0}}[path[i]]
+		head := snake.GetHead()
+		target := head.GetCoords().Add(step)
+		x, y := int(target.X()), int(target.Y())
+		if !helpers.InField(x, y) || walls[target] || snake.Blocked(target) {
+			t.Fatalf("move %d of %s runs into %v", i+1, path, target)
+		}
+		foods := snakemodule.NewFoodSet(1)
+		foods.Put(snakemodule.NormalFood, board.Food[eaten], 0)
+		snake.SetFront(target)
+		if _, ok := snake.Eat(foods); ok {
+			eaten++
+		}
+		snake.Move(target)
+		if eaten == len(board.Food) {
+			if i != len(path)-1 {
+				t.Errorf("all food eaten after %d of %d moves", i+1, len(path))
+			}
+			return
+		}
+	}
+	t.Errorf("%d of %d food eaten", eaten, len(board.Food))
+}
+
+func TestSolverKeepsTheGameRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		board Board
+		want  bool
+	}{
+		{
+			// The only way out is back over the tail, which a snake
+			// of a head and a tail can't do
+			"no reversing at length 2",
+			Board{Size: 3, Walls: []mgl32.Vec2{{0, 1}}, Food: []mgl32.Vec2{{2, 2}}, Snake: []mgl32.Vec2{{1, 0}, {0, 0}}},
+			false,
+		},
+		{
+			// Boxed in by walls and its own body, a longer snake gets
+			// out by following right behind its tail
+			"chasing the tail at length 4",
+			Board{
+				Size:  3,
+				Walls: []mgl32.Vec2{{0, 2}, {1, 2}, {2, 2}, {2, 1}},
+				Food:  []mgl32.Vec2{{2, 0}},
+				Snake: []mgl32.Vec2{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			},
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			solution, ok := Solve(test.board, 20)
+			if ok != test.want {
+				t.Fatalf("solved %v with %q, want %v", ok, solution.Path, test.want)
+			}
+			if ok {
+				replay(t, test.board, solution.Path)
+			}
+		})
+	}
+}
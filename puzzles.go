@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"snakegame/assets"
+	"snakegame/hazard"
+	"snakegame/puzzle"
+	"snakegame/rival"
+	"snakegame/snakemodule"
+	"strings"
+)
+
+// Puzzles in the order they are played, the level is the puzzle index
+var puzzles []puzzle.Puzzle
+var puzzleBoard puzzle.Board
+
+// Moves made on the current puzzle
+var puzzleMoves int
+
+// A direction key was pressed since the last puzzle step
+var stepRequested = false
+
+func loadPuzzles() {
+	f, err := assets.Open("puzzles/puzzles.json")
+	if err != nil {
+		log.Printf("warning: %v, no puzzles", err)
+		return
+	}
+	defer f.Close()
+	puzzles, err = puzzle.Load(f)
+	if err != nil {
+		log.Printf("warning: %v, no puzzles", err)
+	}
+}
+
+// resetPuzzleBoard lays out the puzzle of the level, past the last
+// one the board stays empty
+func resetPuzzleBoard(level int) {
+	now := clock()
+	puzzleMoves = 0
+	puzzleBoard = puzzle.Board{}
+	rivals.Reset(rival.Difficulty{}, nil)
+	powerUps.Clear(now, 0)
+	foods.Clear()
+	if level < len(puzzles) {
+		board, err := puzzles[level].Board(cellsNumber)
+		if err != nil {
+			log.Printf("warning: %v", err)
+		} else {
+			puzzleBoard = board
+		}
+	}
+	hazards.Reset(hazard.Level{Walls: puzzleBoard.Walls}, now)
+	if len(puzzleBoard.Snake) > 1 {
+		snake = snakemodule.NewSnake(puzzleBoard.Snake)
+		cells := puzzleBoard.Snake
+		setDirection(cells[len(cells)-1].Sub(cells[len(cells)-2]))
+	}
+}
+
+// placePuzzleFood shows the next food of the sequence once
+// the one before is eaten
+func placePuzzleFood() {
+	if len(foods.Items) > 0 || eatenFoodCounter >= len(puzzleBoard.Food) {
+		return
+	}
+	foods.Put(snakemodule.NormalFood, puzzleBoard.Food[eatenFoodCounter], clock())
+}
+
+// outOfMoves reports whether the puzzle move limit is used up
+// with food still left to eat
+func outOfMoves() bool {
+	if gameLevel >= len(puzzles) || puzzles[gameLevel].MaxMoves == 0 {
+		return false
+	}
+	return puzzleMoves >= puzzles[gameLevel].MaxMoves && eatenFoodCounter < len(puzzleBoard.Food)
+}
+
+// puzzleBanner is shown before each puzzle
+func puzzleBanner(level int) (string, string) {
+	if level >= len(puzzles) {
+		return "ALL PUZZLES SOLVED", "PRESS ENTER"
+	}
+	title := fmt.Sprintf("%d. %s", level+1, strings.ToUpper(puzzles[level].Name))
+	return title, puzzles[level].Describe()
+}
+
+// puzzleStatus counts the moves against the limit
+func puzzleStatus() string {
+	if gameLevel < len(puzzles) && puzzles[gameLevel].MaxMoves > 0 {
+		return fmt.Sprintf("MOVES %d/%d", puzzleMoves, puzzles[gameLevel].MaxMoves)
+	}
+	return fmt.Sprintf("MOVES %d", puzzleMoves)
+}
@@ -0,0 +1,141 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Canvas is a software Renderer drawing into an image,
+// it mirrors the GL pipeline for headless screenshots and tests
+type Canvas struct {
+	img      *image.RGBA
+	textures []*image.RGBA
+}
+
+func NewCanvas(width, height int) *Canvas {
+	return &Canvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+func (canvas *Canvas) Image() *image.RGBA {
+	return canvas.img
+}
+
+func (canvas *Canvas) Clear(c color.RGBA) {
+	pix := canvas.img.Pix
+	for i := 0; i < len(pix); i += 4 {
+		pix[i], pix[i+1], pix[i+2], pix[i+3] = c.R, c.G, c.B, c.A
+	}
+}
+
+// CreateTexture returns ids starting from 1, like GL does
+func (canvas *Canvas) CreateTexture(imgBytes []uint8, width, height int32) uint32 {
+	canvas.textures = append(canvas.textures, &image.RGBA{
+		Pix:    imgBytes,
+		Stride: int(width) * 4,
+		Rect:   image.Rect(0, 0, int(width), int(height)),
+	})
+	return uint32(len(canvas.textures))
+}
+
+func (canvas *Canvas) DeleteTexture(texture uint32) {
+	if texture > 0 && int(texture) <= len(canvas.textures) {
+		canvas.textures[texture-1] = nil
+	}
+}
+
+// DrawSprite samples the nearest texel and blends it with src alpha,
+// the same as the GL blend function set up in graphics.Init
+func (canvas *Canvas) DrawSprite(sprite Sprite, transform mgl32.Mat4, tint mgl32.Vec4) {
+	if sprite.Texture == 0 || int(sprite.Texture) > len(canvas.textures) {
+		return
+	}
+	texture := canvas.textures[sprite.Texture-1]
+	if texture == nil {
+		return
+	}
+	texWidth := float32(texture.Rect.Dx())
+	texHeight := float32(texture.Rect.Dy())
+	width := float32(canvas.img.Rect.Dx())
+	height := float32(canvas.img.Rect.Dy())
+
+	// Pixel bounding box of the transformed unit quad
+	minX, minY := width, height
+	var maxX, maxY float32
+	for _, corner := range []mgl32.Vec4{{0, 0, 0, 1}, {1, 0, 0, 1}, {0, 1, 0, 1}, {1, 1, 0, 1}} {
+		ndc := transform.Mul4x1(corner)
+		x := (ndc.X() + 1) / 2 * width
+		y := (1 - ndc.Y()) / 2 * height
+		minX = float32(math.Min(float64(minX), float64(x)))
+		maxX = float32(math.Max(float64(maxX), float64(x)))
+		minY = float32(math.Min(float64(minY), float64(y)))
+		maxY = float32(math.Max(float64(maxY), float64(y)))
+	}
+	startX, endX := clampInt(minX, width), clampInt(maxX, width)+1
+	startY, endY := clampInt(minY, height), clampInt(maxY, height)+1
+
+	inverse := transform.Inv()
+	for py := startY; py < endY; py++ {
+		for px := startX; px < endX; px++ {
+			ndcX := (float32(px)+0.5)/width*2 - 1
+			ndcY := 1 - (float32(py)+0.5)/height*2
+			local := inverse.Mul4x1(mgl32.Vec4{ndcX, ndcY, 0, 1})
+			s, t := local.X(), local.Y()
+			if s < 0 || s >= 1 || t < 0 || t >= 1 {
+				continue
+			}
+			u := sprite.UV.X() + s*sprite.UV.Z()
+			v := sprite.UV.Y() + t*sprite.UV.W()
+			tx := clampInt(u*texWidth, texWidth)
+			ty := clampInt((1-v)*texHeight, texHeight)
+			src := texture.RGBAAt(tx, ty)
+			canvas.blend(px, py, src, tint)
+		}
+	}
+}
+
+func (canvas *Canvas) blend(x, y int, src color.RGBA, tint mgl32.Vec4) {
+	i := canvas.img.PixOffset(x, y)
+	pix := canvas.img.Pix[i : i+4 : i+4]
+	alpha := float32(src.A) / 255 * tint.W()
+	for c := 0; c < 3; c++ {
+		value := float32(src.R)
+		switch c {
+		case 1:
+			value = float32(src.G)
+		case 2:
+			value = float32(src.B)
+		}
+		value *= tint[c]
+		pix[c] = uint8(value*alpha + float32(pix[c])*(1-alpha) + 0.5)
+	}
+	pix[3] = uint8(math.Min(255, float64(alpha*255+float32(pix[3])*(1-alpha)+0.5)))
+}
+
+func clampInt(value, limit float32) int {
+	i := int(value)
+	if i < 0 {
+		return 0
+	}
+	if i >= int(limit) {
+		return int(limit) - 1
+	}
+	return i
+}
+
+func (canvas *Canvas) WritePNG(path string) error {
+	return WritePNG(path, canvas.img)
+}
+
+func WritePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
@@ -0,0 +1,52 @@
+package render
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// UV rectangle covering the whole texture
+var FullTextureUV = mgl32.Vec4{0, 0, 1, 1}
+
+var WhiteTint = mgl32.Vec4{1, 1, 1, 1}
+
+// Sprite is a sub-rectangle of a texture.
+// UV holds the bottom left corner, width and height in texture coords.
+type Sprite struct {
+	Texture uint32
+	UV      mgl32.Vec4
+}
+
+// Renderer draws textured unit quads, transformed into clip space.
+// Texture ids are only valid for the renderer that created them.
+type Renderer interface {
+	// CreateTexture takes RGBA pixels, top row first
+	CreateTexture(imgBytes []uint8, width, height int32) uint32
+	DeleteTexture(texture uint32)
+	DrawSprite(sprite Sprite, transform mgl32.Mat4, tint mgl32.Vec4)
+}
+
+// Atlas is a texture split into a grid of equally sized sprites
+type Atlas struct {
+	texture uint32
+	columns int
+	rows    int
+}
+
+func NewAtlas(texture uint32, columns, rows int) Atlas {
+	return Atlas{
+		texture: texture,
+		columns: columns,
+		rows:    rows,
+	}
+}
+
+// Sprite returns the grid cell counted from the top left corner of the image
+func (atlas Atlas) Sprite(column, row int) Sprite {
+	width := 1 / float32(atlas.columns)
+	height := 1 / float32(atlas.rows)
+	// Textures are flipped on load, so the image top is at v = 1
+	u := float32(column) * width
+	v := 1 - float32(row+1)*height
+	return Sprite{
+		Texture: atlas.texture,
+		UV:      mgl32.Vec4{u, v, width, height},
+	}
+}
@@ -0,0 +1,119 @@
+package replay
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"snakegame/powerup"
+	"snakegame/render"
+	"snakegame/scene"
+	"snakegame/snakemodule"
+	"snakegame/theme"
+)
+
+// Frame side in pixels at scale 1
+const baseFrameSize = 400
+
+type ExportOptions struct {
+	Scale       float64
+	FrameRate   int
+	LastSeconds float64
+	Theme       string
+}
+
+var DefaultExportOptions = ExportOptions{
+	Scale:       1,
+	FrameRate:   10,
+	LastSeconds: 10,
+}
+
+// renderFrames draws every sampled frame with the software renderer
+func renderFrames(rec *Recording, options ExportOptions) ([]*image.RGBA, error) {
+	frames := rec.Sample(options.FrameRate, options.LastSeconds)
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("nothing to export")
+	}
+	size := int(baseFrameSize * options.Scale)
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid scale %v", options.Scale)
+	}
+
+	th, err := theme.Load(options.Theme)
+	if err != nil {
+		return nil, err
+	}
+	defer th.Close()
+
+	canvas := render.NewCanvas(size, size)
+	sc := scene.Load(canvas, th, rec.CellsNumber)
+	images := make([]*image.RGBA, 0, len(frames))
+	for _, frame := range frames {
+		foods := snakemodule.FoodSet{Items: frame.Food}
+		snake := snakemodule.NewSnake(frame.Snake)
+
+		canvas.Clear(sc.ClearColor())
+		sc.DrawBackground(sc.BoardBackground(frame.Level))
+		sc.DrawHazards(&frame.Hazards, frame.Time)
+		sc.DrawFood(&foods)
+		sc.DrawPowerUps(&frame.PowerUps)
+		for _, cells := range frame.Rivals {
+			sc.DrawSnakeTinted(snakemodule.NewSnake(cells), scene.RivalTint)
+		}
+		if frame.PowerUps.Active(powerup.Ghost) {
+			sc.DrawSnakeTinted(snake, scene.GhostTint)
+		} else {
+			sc.DrawSnake(snake)
+		}
+		sc.DrawHUD(&frame.PowerUps, &frame.Hazards, frame.Time)
+
+		img := image.NewRGBA(canvas.Image().Rect)
+		copy(img.Pix, canvas.Image().Pix)
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func ExportGIF(rec *Recording, path string, options ExportOptions) error {
+	images, err := renderFrames(rec, options)
+	if err != nil {
+		return err
+	}
+	delay := 100 / options.FrameRate
+	animation := gif.GIF{}
+	for _, img := range images {
+		paletted := image.NewPaletted(img.Rect, palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, img.Rect, img, image.Point{})
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, delay)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gif.EncodeAll(f, &animation)
+}
+
+// ExportPNGFrames writes frame_0000.png, frame_0001.png... into dir
+func ExportPNGFrames(rec *Recording, dir string, options ExportOptions) error {
+	images, err := renderFrames(rec, options)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for i, img := range images {
+		err = render.WritePNG(filepath.Join(dir, fmt.Sprintf("frame_%04d.png", i)), img)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
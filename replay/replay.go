@@ -0,0 +1,97 @@
+package replay
+
+import (
+	"snakegame/hazard"
+	"snakegame/powerup"
+	"snakegame/rival"
+	"snakegame/snakemodule"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Frame is the board state after one game tick
+type Frame struct {
+	Time float64
+	// Level played, it picks the board background
+	Level    int
+	Snake    []mgl32.Vec2
+	Food     []snakemodule.Food
+	PowerUps powerup.State
+	Hazards  hazard.State
+	// Cells of every rival, from tail to head
+	Rivals [][]mgl32.Vec2
+}
+
+// Recording keeps the ticks of one life, oldest first
+type Recording struct {
+	CellsNumber int
+	Frames      []Frame
+	maxFrames   int
+}
+
+func NewRecording(cellsNumber, maxFrames int) *Recording {
+	return &Recording{CellsNumber: cellsNumber, maxFrames: maxFrames}
+}
+
+func (rec *Recording) Record(time float64, level int, snake *snakemodule.Snake, foods *snakemodule.FoodSet, powerUps *powerup.State, hazards *hazard.State, rivals *rival.Group) {
+	if rec.maxFrames > 0 && len(rec.Frames) == rec.maxFrames {
+		rec.Frames = rec.Frames[1:]
+	}
+	var rivalCells [][]mgl32.Vec2
+	for _, r := range rivals.Rivals {
+		rivalCells = append(rivalCells, r.Snake.Cells())
+	}
+	rec.Frames = append(rec.Frames, Frame{
+		Time:     time,
+		Level:    level,
+		Snake:    snake.Cells(),
+		Food:     append([]snakemodule.Food(nil), foods.Items...),
+		PowerUps: powerUps.Copy(),
+		Hazards:  hazards.Copy(),
+		Rivals:   rivalCells,
+	})
+}
+
+func (rec *Recording) Reset() {
+	rec.Frames = nil
+}
+
+// Copy returns a recording safe to use from another goroutine
+func (rec *Recording) Copy() *Recording {
+	frames := make([]Frame, len(rec.Frames))
+	copy(frames, rec.Frames)
+	return &Recording{CellsNumber: rec.CellsNumber, Frames: frames, maxFrames: rec.maxFrames}
+}
+
+// Duration is the time between the first and the last frame
+func (rec *Recording) Duration() float64 {
+	if len(rec.Frames) == 0 {
+		return 0
+	}
+	return rec.Frames[len(rec.Frames)-1].Time - rec.Frames[0].Time
+}
+
+// Sample picks the frames shown at the given frame rate,
+// keeping only the last seconds when lastSeconds is positive
+func (rec *Recording) Sample(frameRate int, lastSeconds float64) []Frame {
+	if len(rec.Frames) == 0 || frameRate <= 0 {
+		return nil
+	}
+	end := rec.Frames[len(rec.Frames)-1].Time
+	start := rec.Frames[0].Time
+	if lastSeconds > 0 && end-lastSeconds > start {
+		start = end - lastSeconds
+	}
+
+	var sampled []Frame
+	step := 1 / float64(frameRate)
+	i := 0
+	for t := start; t <= end+step/2; t += step {
+		// Latest frame not newer than t
+		for i+1 < len(rec.Frames) && rec.Frames[i+1].Time <= t {
+			i++
+		}
+		sampled = append(sampled, rec.Frames[i])
+	}
+	return sampled
+}
@@ -0,0 +1,173 @@
+package rival
+
+import (
+	"math"
+	"math/rand"
+	"snakegame/helpers"
+	"snakegame/snakemodule"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Difficulty sets how many rivals a level has and how well they play
+type Difficulty struct {
+	Count int
+	// Steps it takes a rival to notice where the food went
+	ReactionDelay int
+	// Steps looked ahead to keep out of dead ends
+	Lookahead int
+}
+
+// Rival is a computer controlled snake
+type Rival struct {
+	Snake     *snakemodule.Snake
+	Direction mgl32.Vec2
+	// Food cells seen on the last steps, oldest first
+	seenFood [][]int
+}
+
+// Start is where a rival appears, cells go from tail to head
+type Start struct {
+	Cells     []mgl32.Vec2
+	Direction mgl32.Vec2
+}
+
+// Group runs the rivals of a level
+type Group struct {
+	Rivals     []*Rival
+	Difficulty Difficulty
+	rand       *rand.Rand
+}
+
+func NewGroup(seed int64) *Group {
+	return &Group{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Reset puts the level rivals on their starts, as many as there are starts
+func (group *Group) Reset(difficulty Difficulty, starts []Start) {
+	group.Difficulty = difficulty
+	group.Rivals = nil
+	for i := 0; i < difficulty.Count && i < len(starts); i++ {
+		group.Rivals = append(group.Rivals, &Rival{
+			Snake:     snakemodule.NewSnake(starts[i].Cells),
+			Direction: starts[i].Direction,
+		})
+	}
+}
+
+// Indices returns the field cells taken by the rivals
+func (group *Group) Indices() []int {
+	var indices []int
+	for _, rival := range group.Rivals {
+		indices = append(indices, rival.Snake.Indices()...)
+	}
+	return indices
+}
+
+// Remove takes a crashed rival off the board
+func (group *Group) Remove(rival *Rival) {
+	for i, other := range group.Rivals {
+		if other == rival {
+			group.Rivals = append(group.Rivals[:i], group.Rivals[i+1:]...)
+			return
+		}
+	}
+}
+
+// Next returns the cell the rival moves to. free holds the cells it may
+// enter, food the food cells now. The rival heads for the food
+// as it lay ReactionDelay steps ago.
+func (group *Group) Next(rival *Rival, free map[int]bool, food []int) mgl32.Vec2 {
+	head := rival.Snake.GetHead()
+	headCoords := head.GetCoords()
+	rival.seenFood = append(rival.seenFood, food)
+	if len(rival.seenFood) > group.Difficulty.ReactionDelay+1 {
+		rival.seenFood = rival.seenFood[1:]
+	}
+
+	foodDistances := distancesFrom(rival.seenFood[0], free)
+	bestScore := math.Inf(-1)
+	for _, direction := range turns(rival.Direction) {
+		next := headCoords.Add(direction)
+		index, ok := cellIndex(next)
+		if !ok || !free[index] {
+			continue
+		}
+		// Room to move comes first, then getting closer to food
+		room := lookahead(index, free, map[int]bool{index: true}, group.Difficulty.Lookahead)
+		score := float64(room) * 1000
+		if distance, reachable := foodDistances[index]; reachable {
+			score -= float64(distance)
+		} else {
+			score -= 999
+		}
+		// A little noise so rivals don't move in lockstep
+		score += group.rand.Float64() * 0.5
+		if score > bestScore {
+			bestScore = score
+			rival.Direction = direction
+		}
+	}
+	return headCoords.Add(rival.Direction)
+}
+
+// turns returns going straight, left and right
+func turns(direction mgl32.Vec2) []mgl32.Vec2 {
+	left := mgl32.Vec2{-direction.Y(), direction.X()}
+	return []mgl32.Vec2{direction, left, left.Mul(-1)}
+}
+
+func cellIndex(coords mgl32.Vec2) (int, bool) {
+	x, y := int(coords.X()), int(coords.Y())
+	if !helpers.InField(x, y) {
+		return 0, false
+	}
+	return helpers.CoordsToIndex(x, y), true
+}
+
+// lookahead returns how many steps, up to depth, the rival can
+// still make from the cell without crossing visited cells
+func lookahead(cell int, free, visited map[int]bool, depth int) int {
+	if depth <= 0 {
+		return 0
+	}
+	best := 0
+	for _, next := range helpers.Neighbours(cell) {
+		if !free[next] || visited[next] {
+			continue
+		}
+		visited[next] = true
+		steps := 1 + lookahead(next, free, visited, depth-1)
+		visited[next] = false
+		if steps > best {
+			best = steps
+		}
+		if best == depth {
+			break
+		}
+	}
+	return best
+}
+
+// distancesFrom counts the steps from the nearest of the cells
+// to every free cell reachable from them
+func distancesFrom(cells []int, free map[int]bool) map[int]int {
+	distances := make(map[int]int)
+	var queue []int
+	for _, cell := range cells {
+		distances[cell] = 0
+		queue = append(queue, cell)
+	}
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+		for _, next := range helpers.Neighbours(cell) {
+			if _, seen := distances[next]; seen || !free[next] {
+				continue
+			}
+			distances[next] = distances[cell] + 1
+			queue = append(queue, next)
+		}
+	}
+	return distances
+}
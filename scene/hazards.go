@@ -0,0 +1,80 @@
+package scene
+
+import (
+	"math"
+	"snakegame/hazard"
+	"snakegame/render"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Portal pairs are told apart by color
+var portalTints = []mgl32.Vec4{
+	{0.3, 0.6, 1, 1},
+	{1, 0.5, 0.2, 1},
+	{0.6, 1, 0.4, 1},
+	{1, 0.4, 0.9, 1},
+}
+
+var hiddenSpikeTint = mgl32.Vec4{1, 1, 1, 0.2}
+var warningTint = mgl32.Vec4{1, 0.3, 0.2, 1}
+
+// Walls in themes without the wall sprite
+var plainWallTint = mgl32.Vec4{0.35, 0.3, 0.28, 1}
+
+// DrawHazards draws walls, portals, spike traps and poison, the traps
+// and the poison blink red before they turn dangerous
+func (scene *Scene) DrawHazards(state *hazard.State, now float64) {
+	blinkOn := math.Mod(now, 0.25) < 0.125
+
+	wall, ok := scene.sprites["wall"]
+	wallTint := render.WhiteTint
+	if !ok {
+		wall, wallTint = scene.sprites["bar"], plainWallTint
+	}
+	for _, cell := range state.Level.Walls {
+		scene.drawSprite(wall, cell, 0, 1, wallTint)
+	}
+
+	portal := scene.sprites["portal"]
+	spin := float32(-now * 2)
+	for i, pair := range state.Level.Portals {
+		tint := portalTints[i%len(portalTints)]
+		scene.drawSprite(portal, pair.A, spin, 1, tint)
+		scene.drawSprite(portal, pair.B, spin, 1, tint)
+	}
+
+	spikes := scene.sprites["spikes"]
+	for _, spike := range state.Level.Spikes {
+		switch spike.Phase(now - state.Start) {
+		case hazard.SpikeOn:
+			scene.drawSprite(spikes, spike.Position, 0, 1, render.WhiteTint)
+		case hazard.SpikeWarning:
+			tint := hiddenSpikeTint
+			if blinkOn {
+				tint = warningTint
+			}
+			scene.drawSprite(spikes, spike.Position, 0, 0.8, tint)
+		default:
+			scene.drawSprite(spikes, spike.Position, 0, 0.8, hiddenSpikeTint)
+		}
+	}
+
+	for _, poison := range state.Poison {
+		sprite, ok := scene.sprites[poison.Kind.String()]
+		if !ok {
+			continue
+		}
+		if poison.Active(now) {
+			scene.drawSprite(sprite, poison.Position, 0, 1, render.WhiteTint)
+			continue
+		}
+		// Grows in while blinking
+		grow := 1 - float32((poison.ActiveAt-now)/hazard.PoisonWarningTime)
+		tint := mgl32.Vec4{1, 1, 1, 0.5}
+		if blinkOn {
+			tint = warningTint
+		}
+		scene.drawSprite(sprite, poison.Position, 0, 0.4+0.6*grow, tint)
+	}
+}
@@ -0,0 +1,81 @@
+package scene
+
+import (
+	"snakegame/hazard"
+	"snakegame/powerup"
+	"snakegame/render"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// HUD layout in cell units, from the top left corner of the board
+const (
+	hudMargin   = 0.15
+	hudIconSize = 0.6
+	hudBarWidth = 1.2
+	hudBarSize  = 0.15
+)
+
+// Snake tint while the ghost power-up is active
+var GhostTint = mgl32.Vec4{1, 1, 1, 0.45}
+
+// Tint telling the computer snakes apart from the player
+var RivalTint = mgl32.Vec4{1, 0.45, 0.35, 1}
+
+var hudBarBack = mgl32.Vec4{0, 0, 0, 0.5}
+var hudBarFront = mgl32.Vec4{1, 1, 1, 0.9}
+var boostTint = mgl32.Vec4{1, 0.75, 0.2, 0.95}
+
+func (scene *Scene) DrawPowerUps(state *powerup.State) {
+	for _, item := range state.Items {
+		if sprite, ok := scene.sprites[item.Kind.String()]; ok {
+			scene.drawSprite(sprite, item.Position, 0, 1, render.WhiteTint)
+		}
+	}
+}
+
+// DrawHUD shows the active effects with the time they have left
+func (scene *Scene) DrawHUD(powerUps *powerup.State, hazards *hazard.State, now float64) {
+	x := float32(hudMargin)
+	for _, effect := range powerUps.Effects {
+		x = scene.drawHUDItem(effect.Kind.String(), x, effect.End != 0, effect.Remaining(now))
+	}
+	if hazards.Reversed(now) {
+		scene.drawHUDItem(hazard.ReversePoison.String(), x, true, hazards.ReversedRemaining(now))
+	}
+}
+
+// drawHUDItem draws an icon at x, with a bar for timed effects,
+// and returns where the next item goes
+func (scene *Scene) drawHUDItem(sprite string, x float32, timed bool, remaining float32) float32 {
+	icon, ok := scene.sprites[sprite]
+	if !ok {
+		return x
+	}
+	y := float32(scene.cellsNumber) - hudMargin - hudIconSize
+	scene.drawRect(icon, x, y, hudIconSize, hudIconSize, render.WhiteTint)
+	x += hudIconSize + hudMargin
+	if timed {
+		bar := scene.sprites["bar"]
+		barY := y + (hudIconSize-hudBarSize)/2
+		scene.drawRect(bar, x, barY, hudBarWidth, hudBarSize, hudBarBack)
+		scene.drawRect(bar, x, barY, hudBarWidth*remaining, hudBarSize, hudBarFront)
+		x += hudBarWidth + hudMargin
+	}
+	return x
+}
+
+// DrawBoostMeter shows the boost left in the bottom left corner,
+// lit up while the boost is on
+func (scene *Scene) DrawBoostMeter(meter float64, active bool) {
+	bar, ok := scene.sprites["bar"]
+	if !ok {
+		return
+	}
+	tint := hudBarFront
+	if active {
+		tint = boostTint
+	}
+	scene.drawRect(bar, hudMargin, hudMargin, hudBarWidth, hudBarSize, hudBarBack)
+	scene.drawRect(bar, hudMargin, hudMargin, hudBarWidth*float32(meter), hudBarSize, tint)
+}
@@ -0,0 +1,134 @@
+package scene
+
+import (
+	"fmt"
+	"snakegame/daily"
+	"snakegame/mode"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Font pixel sizes in cells
+const (
+	titlePixel = 0.1
+	textPixel  = 0.07
+)
+
+var panelTint = mgl32.Vec4{0, 0, 0, 0.8}
+var textTint = mgl32.Vec4{1, 1, 1, 1}
+var highlightTint = mgl32.Vec4{1, 0.85, 0.2, 1}
+
+// Leaderboard entries listed under the selected mode
+const shownEntries = 3
+
+// DrawModeSelect shows the selected mode and its best runs
+// in a band along the top of the start screen
+func (scene *Scene) DrawModeSelect(selected mode.Mode, board mode.Leaderboard) {
+	entries := board[selected.String()]
+	line := "NO RUNS YET"
+	if len(entries) > 0 {
+		line = ""
+		for i := 0; i < shownEntries && i < len(entries); i++ {
+			line += fmt.Sprintf("%d. %s  ", i+1, FormatScore(selected, entries[i].Score))
+		}
+	}
+	scene.drawSelect(selected.Rules().Title, strings.TrimSpace(line))
+}
+
+// DrawDailySelect shows the date of the daily challenge
+// with the best score and the attempts made on it
+func (scene *Scene) DrawDailySelect(date string, record daily.Record) {
+	line := date + "  NOT PLAYED YET"
+	if record.Attempts > 0 {
+		line = fmt.Sprintf("%s  BEST %d  TRIES %d", date, record.Best, record.Attempts)
+	}
+	scene.drawSelect(mode.Daily.Rules().Title, line)
+}
+
+// DrawDifficultySelect shows the preset in a band along the bottom
+// of the start screen
+func (scene *Scene) DrawDifficultySelect(title string, adaptive bool) {
+	line := "W/S " + title + "  X ADAPTIVE OFF"
+	if adaptive {
+		line = "W/S " + title + "  X ADAPTIVE ON"
+	}
+	scene.drawPanel(0.05, 0.55)
+	scene.DrawTextCentered(line, 0.15, textPixel, highlightTint)
+}
+
+// DrawBanner shows a title and a line across the middle of the board
+func (scene *Scene) DrawBanner(title, line string) {
+	middle := float32(scene.cellsNumber) / 2
+	scene.drawPanel(middle-0.8, 1.6)
+	scene.DrawTextCentered(title, middle, titlePixel, highlightTint)
+	scene.DrawTextCentered(line, middle-0.55, textPixel, textTint)
+}
+
+func (scene *Scene) drawSelect(title, line string) {
+	top := float32(scene.cellsNumber)
+	scene.drawPanel(top-1.3, 1.2)
+	scene.DrawTextCentered("< "+title+" >", top-0.7, titlePixel, highlightTint)
+	scene.DrawTextCentered(line, top-1.15, textPixel, textTint)
+}
+
+// DrawSummary shows how the run went, under the game over picture
+func (scene *Scene) DrawSummary(summary mode.Summary) {
+	scene.drawPanel(0.2, 3.1)
+	scene.DrawTextCentered(summary.Mode.Rules().Title, 2.55, titlePixel, highlightTint)
+	scene.DrawTextCentered("SCORE "+FormatScore(summary.Mode, summary.Score), 1.85, titlePixel, textTint)
+	switch {
+	case summary.Rank == 1:
+		scene.DrawTextCentered("NEW BEST", 1.3, textPixel, highlightTint)
+	case summary.Rank > 0:
+		scene.DrawTextCentered(fmt.Sprintf("RANK %d  BEST %s", summary.Rank, FormatScore(summary.Mode, summary.Best)), 1.3, textPixel, textTint)
+	default:
+		scene.DrawTextCentered("BEST "+FormatScore(summary.Mode, summary.Best), 1.3, textPixel, textTint)
+	}
+	scene.DrawTextCentered(fmt.Sprintf("LEVEL %d  LENGTH %d", summary.Level+1, summary.Length), 0.85, textPixel, textTint)
+	scene.DrawTextCentered(FormatSeconds(summary.Seconds)+"  "+summary.Reason, 0.4, textPixel, textTint)
+}
+
+// DrawRunStatus shows the score and the clock of the run in the top right corner,
+// the clock counts down in modes with a time limit
+func (scene *Scene) DrawRunStatus(current mode.Mode, score int, seconds float64) {
+	rules := current.Rules()
+	text := FormatScore(current, score)
+	if rules.TimeLimit > 0 {
+		text += "  " + FormatSeconds(rules.TimeLimit-seconds)
+	} else if !rules.TimeScore {
+		text += "  " + FormatSeconds(seconds)
+	}
+	scene.DrawStatus(text)
+}
+
+// DrawStatus shows a short line in the top right corner
+func (scene *Scene) DrawStatus(text string) {
+	size := float32(scene.cellsNumber)
+	x := size - hudMargin - TextWidth(text, textPixel)
+	y := size - hudMargin - 5*textPixel
+	scene.DrawText(text, x, y, textPixel, textTint)
+}
+
+func (scene *Scene) drawPanel(y, height float32) {
+	if bar, ok := scene.sprites["bar"]; ok {
+		scene.drawRect(bar, 0.5, y, float32(scene.cellsNumber)-1, height, panelTint)
+	}
+}
+
+// FormatScore writes seconds scores as a clock and points as a number
+func FormatScore(current mode.Mode, score int) string {
+	if current.Rules().TimeScore {
+		return FormatSeconds(float64(score))
+	}
+	return fmt.Sprint(score)
+}
+
+// FormatSeconds writes m:ss, negative times as 0:00
+func FormatSeconds(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	whole := int(seconds)
+	return fmt.Sprintf("%d:%02d", whole/60, whole%60)
+}
@@ -0,0 +1,180 @@
+package scene
+
+import (
+	"image/color"
+	"snakegame/animation"
+	"snakegame/render"
+	"snakegame/snakemodule"
+	"snakegame/theme"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Scene draws the game screens with any render.Renderer,
+// so the window and the software canvas show the same picture
+type Scene struct {
+	renderer    render.Renderer
+	cellsNumber int
+	textures    []uint32
+	Theme       *theme.Theme
+
+	Background       uint32
+	StartGame        uint32
+	GameOver         uint32
+	Levels           []uint32
+	levelBackgrounds []uint32
+
+	sprites      map[string]render.Sprite
+	snakeSprites [4]render.Sprite
+}
+
+func Load(renderer render.Renderer, th *theme.Theme, cellsNumber int) *Scene {
+	scene := Scene{renderer: renderer, cellsNumber: cellsNumber, Theme: th}
+	manifest := th.Manifest
+	scene.Background = scene.loadTexture(manifest.Background)
+	scene.StartGame = scene.loadTexture(manifest.StartGame)
+	scene.GameOver = scene.loadTexture(manifest.GameOver)
+	for _, level := range manifest.Levels {
+		scene.Levels = append(scene.Levels, scene.loadTexture(level))
+	}
+	for i := range manifest.LevelBackgrounds {
+		scene.levelBackgrounds = append(scene.levelBackgrounds, scene.loadTexture(th.LevelBackground(i)))
+	}
+
+	atlasManifest := manifest.Atlas
+	atlas := render.NewAtlas(scene.loadTexture(atlasManifest.Image), atlasManifest.Columns, atlasManifest.Rows)
+	scene.sprites = make(map[string]render.Sprite)
+	for name, cell := range manifest.Sprites {
+		// Themes with a smaller atlas lack the newer sprites
+		if cell[0] < atlasManifest.Columns && cell[1] < atlasManifest.Rows {
+			scene.sprites[name] = atlas.Sprite(cell[0], cell[1])
+		}
+	}
+	scene.snakeSprites = [...]render.Sprite{
+		snakemodule.Head:     scene.sprites["head"],
+		snakemodule.Straight: scene.sprites["straight"],
+		snakemodule.Corner:   scene.sprites["corner"],
+		snakemodule.Tail:     scene.sprites["tail"],
+	}
+	return &scene
+}
+
+func (scene *Scene) loadTexture(name string) uint32 {
+	texture := scene.renderer.CreateTexture(scene.Theme.LoadImage(name))
+	scene.textures = append(scene.textures, texture)
+	return texture
+}
+
+// Release frees the textures, the scene can't be drawn afterwards
+func (scene *Scene) Release() {
+	for _, texture := range scene.textures {
+		scene.renderer.DeleteTexture(texture)
+	}
+	scene.textures = nil
+}
+
+// Screen shown before the level starts, the last one for levels past the end
+func (scene *Scene) LevelScreen(level int) uint32 {
+	if len(scene.Levels) == 0 {
+		return scene.Background
+	}
+	if level >= len(scene.Levels) {
+		level = len(scene.Levels) - 1
+	}
+	return scene.Levels[level]
+}
+
+// ClearColor fills what the backgrounds don't cover
+func (scene *Scene) ClearColor() color.RGBA {
+	rgb := scene.Theme.Manifest.LetterboxColor
+	return color.RGBA{uint8(rgb[0] * 255), uint8(rgb[1] * 255), uint8(rgb[2] * 255), 255}
+}
+
+// BoardBackground is drawn under the snake on the level
+func (scene *Scene) BoardBackground(level int) uint32 {
+	if level >= 0 && level < len(scene.levelBackgrounds) {
+		return scene.levelBackgrounds[level]
+	}
+	return scene.Background
+}
+
+func (scene *Scene) DrawBackground(texture uint32) {
+	scale := mgl32.Scale3D(2, 2, 1)
+	translate := mgl32.Translate3D(-1, -1, 0)
+	transform := translate.Mul4(scale)
+	sprite := render.Sprite{Texture: texture, UV: render.FullTextureUV}
+	scene.renderer.DrawSprite(sprite, transform, render.WhiteTint)
+}
+
+func (scene *Scene) DrawSnake(snake *snakemodule.Snake) {
+	scene.DrawSnakeTinted(snake, render.WhiteTint)
+}
+
+func (scene *Scene) DrawSnakeTinted(snake *snakemodule.Snake, tint mgl32.Vec4) {
+	snake.Draw(func(part snakemodule.BodyPart, vec mgl32.Vec2, angle float32) {
+		scene.drawSprite(scene.snakeSprites[part], vec, angle, 1, tint)
+	})
+}
+
+// Tints of the plain food sprite for themes without the food kind sprites
+var foodTints = [snakemodule.FoodKindCount]mgl32.Vec4{
+	snakemodule.NormalFood: render.WhiteTint,
+	snakemodule.BonusFood:  {0.7, 0.4, 1, 1},
+	snakemodule.GoldenFood: {1, 0.85, 0.2, 1},
+	snakemodule.ShrinkFood: {0.3, 0.6, 1, 1},
+	snakemodule.PreyFood:   {0.6, 0.5, 0.4, 1},
+}
+
+func (scene *Scene) DrawFood(foods *snakemodule.FoodSet) {
+	for _, food := range foods.Items {
+		scene.DrawFoodItem(food)
+	}
+}
+
+func (scene *Scene) DrawFoodItem(food snakemodule.Food) {
+	sprite, tint := scene.foodSprite(food.Kind)
+	food.Draw(func(vec mgl32.Vec2) {
+		scene.drawSprite(sprite, vec, 0, 1, tint)
+	})
+}
+
+// foodSprite falls back to the tinted plain food sprite
+func (scene *Scene) foodSprite(kind snakemodule.FoodKind) (render.Sprite, mgl32.Vec4) {
+	if sprite, ok := scene.sprites[kind.String()]; ok {
+		return sprite, render.WhiteTint
+	}
+	return scene.sprites["food"], foodTints[kind]
+}
+
+func (scene *Scene) DrawAnimations(system *animation.System, now float64) {
+	system.Draw(now, func(name string, vec mgl32.Vec2, scale float32, tint mgl32.Vec4) {
+		sprite, ok := scene.sprites[name]
+		if !ok {
+			return
+		}
+		scene.drawSprite(sprite, vec, 0, scale, tint)
+	})
+}
+
+// drawRect stretches the sprite over a rectangle in cell coords
+func (scene *Scene) drawRect(sprite render.Sprite, x, y, width, height float32, tint mgl32.Vec4) {
+	scaleFactor := float32(2.0 / float32(scene.cellsNumber))
+	translate := mgl32.Translate3D(x*scaleFactor-1, y*scaleFactor-1, 0)
+	scale := mgl32.Scale3D(width*scaleFactor, height*scaleFactor, 1)
+	scene.renderer.DrawSprite(sprite, translate.Mul4(scale), tint)
+}
+
+func (scene *Scene) drawSprite(sprite render.Sprite, vec mgl32.Vec2, angle, spriteScale float32, tint mgl32.Vec4) {
+	scaleFactor := float32(2.0 / float32(scene.cellsNumber))
+	scale := mgl32.Scale3D(scaleFactor, scaleFactor, 1)
+	xPos := vec.X()*scaleFactor - 1
+	yPos := vec.Y()*scaleFactor - 1
+	translate := mgl32.Translate3D(xPos, yPos, 0)
+	// Rotate and scale around the cell center
+	rotate := mgl32.Translate3D(0.5, 0.5, 0).
+		Mul4(mgl32.HomogRotate3DZ(angle)).
+		Mul4(mgl32.Scale3D(spriteScale, spriteScale, 1)).
+		Mul4(mgl32.Translate3D(-0.5, -0.5, 0))
+	transform := translate.Mul4(scale).Mul4(rotate)
+	scene.renderer.DrawSprite(sprite, transform, tint)
+}
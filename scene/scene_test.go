@@ -0,0 +1,154 @@
+package scene
+
+import (
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"snakegame/daily"
+	"snakegame/hazard"
+	"snakegame/mode"
+	"snakegame/powerup"
+	"snakegame/render"
+	"snakegame/rival"
+	"snakegame/snakemodule"
+	"snakegame/theme"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+// Side of the golden images in pixels
+const goldenSize = 200
+
+// Largest difference allowed in a color channel, floating point
+// rounding differs a little between platforms
+const goldenTolerance = 3
+
+// The screens as the game draws them, with the default theme
+var screens = []struct {
+	name string
+	draw func(scene *Scene)
+}{
+	{"start", func(scene *Scene) {
+		board := mode.Leaderboard{mode.Campaign.String(): {{Score: 42}, {Score: 17}}}
+		scene.DrawBackground(scene.StartGame)
+		scene.DrawModeSelect(mode.Campaign, board)
+		scene.DrawDifficultySelect("NORMAL", true)
+	}},
+	{"daily", func(scene *Scene) {
+		scene.DrawBackground(scene.StartGame)
+		scene.DrawDailySelect("2024-03-01", daily.Record{Best: 12, Attempts: 3})
+	}},
+	{"level", func(scene *Scene) {
+		scene.DrawBackground(scene.LevelScreen(0))
+	}},
+	{"banner", func(scene *Scene) {
+		scene.DrawBackground(scene.BoardBackground(2))
+		scene.DrawBanner("LEVEL 3", "PRESS ENTER")
+	}},
+	{"game", func(scene *Scene) {
+		var hazards hazard.State
+		hazards.Reset(hazard.Level{
+			Walls:   []mgl32.Vec2{{5, 5}, {5, 6}},
+			Spikes:  []hazard.Spike{{Position: mgl32.Vec2{2, 7}, Period: 2, On: 1}},
+			Portals: []hazard.Portal{{A: mgl32.Vec2{1, 8}, B: mgl32.Vec2{8, 1}}},
+		}, 0)
+		foods := snakemodule.NewFoodSet(1)
+		foods.Put(snakemodule.NormalFood, mgl32.Vec2{7, 7}, 0)
+		foods.Put(snakemodule.GoldenFood, mgl32.Vec2{3, 4}, 0)
+		powerUps := powerup.NewState(1)
+		powerUps.Items = []powerup.Item{{Kind: powerup.Magnet, Position: mgl32.Vec2{8, 4}, ExpiresAt: 10}}
+		powerUps.Effects = []powerup.Effect{{Kind: powerup.Shield}}
+		snake := snakemodule.NewSnake([]mgl32.Vec2{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}})
+		rivals := rival.NewGroup(1)
+		rivals.Reset(rival.Difficulty{Count: 1}, []rival.Start{{Cells: []mgl32.Vec2{{9, 2}, {8, 2}, {7, 2}}}})
+
+		scene.DrawBackground(scene.BoardBackground(0))
+		scene.DrawHazards(&hazards, 0.5)
+		scene.DrawFood(foods)
+		scene.DrawPowerUps(powerUps)
+		for _, r := range rivals.Rivals {
+			scene.DrawSnakeTinted(r.Snake, RivalTint)
+		}
+		scene.DrawSnake(snake)
+		scene.DrawHUD(powerUps, &hazards, 0.5)
+		scene.DrawRunStatus(mode.Campaign, 7, 65)
+		scene.DrawBoostMeter(0.6, false)
+	}},
+	{"gameover", func(scene *Scene) {
+		scene.DrawBackground(scene.GameOver)
+		scene.DrawSummary(mode.Summary{Mode: mode.Campaign, Score: 7, Best: 42, Level: 1, Length: 9, Seconds: 65, Reason: "wall"})
+	}},
+}
+
+func TestScreensMatchGoldenImages(t *testing.T) {
+	th, err := theme.Load(theme.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer th.Close()
+	for _, screen := range screens {
+		t.Run(screen.name, func(t *testing.T) {
+			canvas := render.NewCanvas(goldenSize, goldenSize)
+			scene := Load(canvas, th, 10)
+			canvas.Clear(scene.ClearColor())
+			screen.draw(scene)
+
+			path := filepath.Join("testdata", screen.name+".png")
+			if *update {
+				err := os.MkdirAll("testdata", 0755)
+				if err == nil {
+					err = canvas.WritePNG(path)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			golden, err := readPNG(path)
+			if err != nil {
+				t.Fatalf("%v, run go test ./scene -update to write it", err)
+			}
+			if x, y, ok := matches(canvas.Image(), golden); !ok {
+				failed := filepath.Join(t.TempDir(), screen.name+".png")
+				canvas.WritePNG(failed)
+				t.Errorf("pixel %d,%d differs from %s, the screen is in %s", x, y, path, failed)
+			}
+		})
+	}
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// matches compares the images channel by channel,
+// returning the first pixel that is too far off
+func matches(got *image.RGBA, want image.Image) (int, int, bool) {
+	if got.Bounds() != want.Bounds() {
+		return 0, 0, false
+	}
+	bounds := got.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, a1 := got.At(x, y).RGBA()
+			r2, g2, b2, a2 := want.At(x, y).RGBA()
+			for _, pair := range [][2]uint32{{r1, r2}, {g1, g2}, {b1, b2}, {a1, a2}} {
+				difference := int(pair[0]>>8) - int(pair[1]>>8)
+				if difference > goldenTolerance || difference < -goldenTolerance {
+					return x, y, false
+				}
+			}
+		}
+	}
+	return 0, 0, true
+}
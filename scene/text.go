@@ -0,0 +1,99 @@
+package scene
+
+import (
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Glyphs of the block font, 3 by 5 pixels from the top row down.
+// Lower case is drawn as upper case, other runes as spaces.
+var glyphs = map[rune][5]string{
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"##.", "..#", ".#.", "#..", "###"},
+	'3': {"##.", "..#", ".#.", "..#", "##."},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "##.", "..#", "##."},
+	'6': {".##", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "##."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+	'-': {"...", "...", "###", "...", "..."},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	'<': {"..#", ".#.", "#..", ".#.", "..#"},
+	'>': {"#..", ".#.", "..#", ".#.", "#.."},
+}
+
+// Glyph advance in font pixels, one pixel of spacing included
+const glyphAdvance = 4
+
+// TextWidth is the width in cells of the text drawn with the pixel size
+func TextWidth(text string, pixel float32) float32 {
+	if text == "" {
+		return 0
+	}
+	return float32(len([]rune(text))*glyphAdvance-1) * pixel
+}
+
+// DrawText draws the text with its bottom left corner at x, y in cell coords,
+// pixel is the size of one font pixel in cells
+func (scene *Scene) DrawText(text string, x, y, pixel float32, tint mgl32.Vec4) {
+	bar, ok := scene.sprites["bar"]
+	if !ok {
+		return
+	}
+	for _, char := range strings.ToUpper(text) {
+		glyph := glyphs[char]
+		for row, line := range glyph {
+			rowY := y + float32(4-row)*pixel
+			// One rect per run of set pixels
+			for start := 0; start < len(line); start++ {
+				if line[start] != '#' {
+					continue
+				}
+				end := start
+				for end+1 < len(line) && line[end+1] == '#' {
+					end++
+				}
+				scene.drawRect(bar, x+float32(start)*pixel, rowY, float32(end-start+1)*pixel, pixel, tint)
+				start = end
+			}
+		}
+		x += glyphAdvance * pixel
+	}
+}
+
+// DrawTextCentered centers the text horizontally on the board
+func (scene *Scene) DrawTextCentered(text string, y, pixel float32, tint mgl32.Vec4) {
+	x := (float32(scene.cellsNumber) - TextWidth(text, pixel)) / 2
+	scene.DrawText(text, x, y, pixel, tint)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"snakegame/events"
+	"snakegame/mode"
+	"snakegame/render"
+	"snakegame/scene"
+	"snakegame/theme"
+	"strings"
+)
+
+// Software rendering of the current frame
+var screenshotPath = flag.String("screenshot", "screenshot.png", "PNG file written on F12 or by -render-screen")
+
+// Screens -render-screen can draw
+var screenNames = []string{"start", "level", "game", "gameover"}
+
+var screenName = flag.String("render-screen", "", "draw a screen into the -screenshot file without opening a window and exit: "+strings.Join(screenNames, ", "))
+
+// takeScreenshot renders the current frame in software and saves it
+func takeScreenshot() {
+	canvas := render.NewCanvas(windowWidth, windowHeight)
+	sc := scene.Load(canvas, gameScene.Theme, cellsNumber)
+	canvas.Clear(sc.ClearColor())
+	drawFrame(sc)
+	err := canvas.WritePNG(*screenshotPath)
+	if err != nil {
+		fmt.Println("screenshot failed:", err)
+		return
+	}
+	fmt.Println("screenshot saved to", *screenshotPath)
+}
+
+// renderScreen sets a fresh game of the saved mode up on the named
+// screen and saves it like a screenshot, returning the exit code
+func renderScreen(name string) int {
+	startGame, showLevel, gameOver = false, false, false
+	resetGame(gameMode.Rules().Level, startLength())
+	switch name {
+	case "start":
+		startGame = true
+	case "level":
+		showLevel = true
+	case "game":
+	case "gameover":
+		gameOver = true
+		runSummary = mode.Summary{
+			Mode:   gameMode,
+			Score:  runScore(),
+			Best:   leaderboard.Best(gameMode),
+			Level:  gameLevel,
+			Length: snake.Length(),
+			Reason: events.HitWall.String(),
+		}
+	default:
+		fmt.Printf("unknown screen %q, pick one of %s\n", name, strings.Join(screenNames, ", "))
+		return 2
+	}
+
+	th, err := theme.Load(currentSettings.ThemeFor(gameLevel))
+	if err != nil {
+		log.Printf("warning: %v, using the default theme", err)
+		th, err = theme.Load(theme.Default)
+	}
+	if err != nil {
+		fmt.Println("screenshot failed:", err)
+		return 1
+	}
+	defer th.Close()
+	canvas := render.NewCanvas(windowWidth, windowHeight)
+	sc := scene.Load(canvas, th, cellsNumber)
+	canvas.Clear(sc.ClearColor())
+	drawFrame(sc)
+	err = canvas.WritePNG(*screenshotPath)
+	if err != nil {
+		fmt.Println("screenshot failed:", err)
+		return 1
+	}
+	fmt.Println("screenshot saved to", *screenshotPath)
+	return 0
+}
@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Settings are the player choices kept between runs
+type Settings struct {
+	Theme string `json:"theme"`
+	// Theme overrides by level number
+	LevelThemes map[int]string `json:"levelThemes,omitempty"`
+	Window      Window         `json:"window"`
+	Muted       bool           `json:"muted"`
+	// Game mode selected on the start screen
+	Mode string `json:"mode,omitempty"`
+	// Difficulty preset, and whether the speed adapts to the player
+	Difficulty string `json:"difficulty,omitempty"`
+	Adaptive   bool   `json:"adaptive,omitempty"`
+}
+
+// Window is the last window mode, windowed geometry is in screen coords
+type Window struct {
+	Mode    string `json:"mode"`
+	Monitor int    `json:"monitor"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+}
+
+// Path of the settings file in the user config dir
+func Path() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "settings.json"
+	}
+	return filepath.Join(dir, "snakegame", "settings.json")
+}
+
+// Load returns the saved settings, or the defaults if there are none
+func Load() Settings {
+	var settings Settings
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("warning: %v, using default settings", err)
+		}
+		return settings
+	}
+	err = json.Unmarshal(data, &settings)
+	if err != nil {
+		log.Printf("warning: settings %s: %v, using default settings", Path(), err)
+		return Settings{}
+	}
+	return settings
+}
+
+func (settings Settings) Save() error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(Path()), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(), data, 0644)
+}
+
+// ThemeFor returns the theme used on the level
+func (settings Settings) ThemeFor(level int) string {
+	if name, ok := settings.LevelThemes[level]; ok {
+		return name
+	}
+	return settings.Theme
+}
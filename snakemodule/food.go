@@ -0,0 +1,325 @@
+package snakemodule
+
+import (
+	"math"
+	"math/rand"
+	"snakegame/helpers"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type FoodKind int
+
+const (
+	NormalFood FoodKind = iota
+	// Worth more, disappears after a while
+	BonusFood
+	// Grows the snake by several segments
+	GoldenFood
+	// Removes tail segments
+	ShrinkFood
+	// Runs around the board
+	PreyFood
+	FoodKindCount
+)
+
+// FoodType is what eating a kind of food does
+type FoodType struct {
+	Name string
+	// Counted towards the level food limit
+	Points int
+	// Segments added, negative ones are cut from the tail
+	Growth int
+	// Seconds before the food disappears, 0 keeps it
+	Lifetime float64
+	// Snake steps between two moves of the food, 0 keeps it still
+	MoveEvery int
+	// Moving food runs away from the head instead of wandering
+	Flees bool
+}
+
+var FoodTypes = [FoodKindCount]FoodType{
+	NormalFood: {Name: "food", Points: 1, Growth: 1},
+	BonusFood:  {Name: "food_bonus", Points: 3, Growth: 1, Lifetime: 5},
+	GoldenFood: {Name: "food_golden", Points: 1, Growth: 3},
+	ShrinkFood: {Name: "food_shrink", Points: 1, Growth: -2},
+	PreyFood:   {Name: "food_prey", Points: 2, Growth: 1, MoveEvery: 2, Flees: true},
+}
+
+func (kind FoodKind) Type() FoodType {
+	return FoodTypes[kind]
+}
+
+// Sprite name of the kind, normal food is "food"
+func (kind FoodKind) String() string {
+	return FoodTypes[kind].Name
+}
+
+// FoodWeights are the relative spawn chances of the kinds
+type FoodWeights [FoodKindCount]int
+
+type Food struct {
+	cell Cell
+	Kind FoodKind
+	// Time the food disappears, 0 keeps it
+	ExpiresAt float64
+	// Snake steps since the food last moved
+	steps int
+}
+
+func (food *Food) GetCoords() mgl32.Vec2 {
+	return food.cell.coords
+}
+
+func (food *Food) SetCoords(vec mgl32.Vec2) {
+	food.cell.coords = vec
+}
+
+func (food *Food) Draw(draw func(vec mgl32.Vec2)) {
+	position := food.cell.coords
+	draw(position)
+}
+
+// FoodSet is the food lying on the board
+type FoodSet struct {
+	Items []Food
+	rand  *rand.Rand
+}
+
+func NewFoodSet(seed int64) *FoodSet {
+	return &FoodSet{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (set *FoodSet) Clear() {
+	set.Items = nil
+}
+
+// Add places food of the kind on a random cell out of possibleCells
+func (set *FoodSet) Add(kind FoodKind, possibleCells []int, now float64) bool {
+	free := helpers.CellsDifference(possibleCells, set.Indices())
+	if len(free) == 0 {
+		return false
+	}
+	x, y := helpers.IndexToCoords(free[set.rand.Intn(len(free))])
+	food := Food{Kind: kind}
+	food.cell.coords = mgl32.Vec2{float32(x), float32(y)}
+	if lifetime := kind.Type().Lifetime; lifetime > 0 {
+		food.ExpiresAt = now + lifetime
+	}
+	set.Items = append(set.Items, food)
+	return true
+}
+
+// Put places food of the kind on the given cell
+func (set *FoodSet) Put(kind FoodKind, coords mgl32.Vec2, now float64) {
+	food := Food{Kind: kind}
+	food.cell.coords = coords
+	if lifetime := kind.Type().Lifetime; lifetime > 0 {
+		food.ExpiresAt = now + lifetime
+	}
+	set.Items = append(set.Items, food)
+}
+
+// Fill adds food until there are count items, keeping one normal food
+// on the board so the level can always be finished
+func (set *FoodSet) Fill(count int, weights FoodWeights, possibleCells []int, now float64) {
+	for len(set.Items) < count {
+		kind := NormalFood
+		if set.Count(NormalFood) > 0 {
+			kind = set.pick(weights)
+		}
+		if !set.Add(kind, possibleCells, now) {
+			return
+		}
+	}
+}
+
+func (set *FoodSet) pick(weights FoodWeights) FoodKind {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	if total <= 0 {
+		return NormalFood
+	}
+	n := set.rand.Intn(total)
+	for kind, weight := range weights {
+		if n < weight {
+			return FoodKind(kind)
+		}
+		n -= weight
+	}
+	return NormalFood
+}
+
+// Count returns how many items of the kind are on the board
+func (set *FoodSet) Count(kind FoodKind) int {
+	count := 0
+	for _, food := range set.Items {
+		if food.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Expire removes the food past its lifetime
+func (set *FoodSet) Expire(now float64) {
+	kept := set.Items[:0]
+	for _, food := range set.Items {
+		if food.ExpiresAt == 0 || food.ExpiresAt > now {
+			kept = append(kept, food)
+		}
+	}
+	set.Items = kept
+}
+
+func (set *FoodSet) Remove(i int) {
+	set.Items = append(set.Items[:i], set.Items[i+1:]...)
+}
+
+// Indices returns the field cells taken by food
+func (set *FoodSet) Indices() []int {
+	indices := make([]int, len(set.Items))
+	for i, food := range set.Items {
+		indices[i] = helpers.CoordsToIndex(int(food.cell.coords.X()), int(food.cell.coords.Y()))
+	}
+	return indices
+}
+
+func (set *FoodSet) Draw(draw func(kind FoodKind, vec mgl32.Vec2)) {
+	for _, food := range set.Items {
+		draw(food.Kind, food.cell.coords)
+	}
+}
+
+// Pull moves the food within radius one cell closer to target,
+// onto free cells only
+func (set *FoodSet) Pull(target mgl32.Vec2, radius float32, freeCells []int) {
+	free := make(map[int]bool, len(freeCells))
+	for _, index := range freeCells {
+		free[index] = true
+	}
+	for i := range set.Items {
+		coords := set.Items[i].cell.coords
+		diff := target.Sub(coords)
+		if diff.Len() > radius {
+			continue
+		}
+		step := mgl32.Vec2{sign(diff.X()), 0}
+		if math.Abs(float64(diff.Y())) > math.Abs(float64(diff.X())) {
+			step = mgl32.Vec2{0, sign(diff.Y())}
+		}
+		next := coords.Add(step)
+		index := helpers.CoordsToIndex(int(next.X()), int(next.Y()))
+		if !free[index] {
+			continue
+		}
+		free[index] = false
+		free[helpers.CoordsToIndex(int(coords.X()), int(coords.Y()))] = true
+		set.Items[i].cell.coords = next
+	}
+}
+
+func sign(value float32) float32 {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	}
+	return 0
+}
+
+// Step moves the food that runs around, called once per snake step.
+// Food only moves onto free cells, so it stays off walls and the snake.
+func (set *FoodSet) Step(head mgl32.Vec2, freeCells []int) {
+	free := make(map[int]bool, len(freeCells))
+	for _, index := range freeCells {
+		free[index] = true
+	}
+	var distances map[int]int
+	for i := range set.Items {
+		food := &set.Items[i]
+		foodType := food.Kind.Type()
+		if foodType.MoveEvery == 0 {
+			continue
+		}
+		food.steps++
+		if food.steps < foodType.MoveEvery {
+			continue
+		}
+		food.steps = 0
+
+		index := helpers.CoordsToIndex(int(food.cell.coords.X()), int(food.cell.coords.Y()))
+		var moves []int
+		for _, next := range helpers.Neighbours(index) {
+			if free[next] {
+				moves = append(moves, next)
+			}
+		}
+		if len(moves) == 0 {
+			continue
+		}
+		next := moves[set.rand.Intn(len(moves))]
+		if foodType.Flees {
+			if distances == nil {
+				distances = stepDistances(head, free, set.Indices())
+			}
+			// Staying put is fine when no move gets farther
+			next = set.farthest(append([]int{index}, moves...), distances)
+			if next == index {
+				continue
+			}
+		}
+		free[next] = false
+		free[index] = true
+		x, y := helpers.IndexToCoords(next)
+		food.cell.coords = mgl32.Vec2{float32(x), float32(y)}
+	}
+}
+
+// stepDistances counts the snake steps from the head to every free
+// or food cell reachable from it
+func stepDistances(head mgl32.Vec2, free map[int]bool, foodCells []int) map[int]int {
+	passable := make(map[int]bool, len(free)+len(foodCells))
+	for index, isFree := range free {
+		passable[index] = isFree
+	}
+	for _, index := range foodCells {
+		passable[index] = true
+	}
+	start := helpers.CoordsToIndex(int(head.X()), int(head.Y()))
+	distances := map[int]int{start: 0}
+	queue := []int{start}
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+		for _, next := range helpers.Neighbours(cell) {
+			if _, seen := distances[next]; seen || !passable[next] {
+				continue
+			}
+			distances[next] = distances[cell] + 1
+			queue = append(queue, next)
+		}
+	}
+	return distances
+}
+
+// farthest picks the move the head needs the most steps to reach,
+// cells it can't reach at all are the safest
+func (set *FoodSet) farthest(moves []int, distances map[int]int) int {
+	best := moves[0]
+	bestDistance := -1
+	for _, move := range moves {
+		distance, reachable := distances[move]
+		if !reachable {
+			distance = math.MaxInt32
+		}
+		// Random tie breaks keep the prey from running in lines
+		if distance > bestDistance || distance == bestDistance && set.rand.Intn(2) == 0 {
+			best, bestDistance = move, distance
+		}
+	}
+	return best
+}
@@ -0,0 +1,33 @@
+package snakemodule
+
+import (
+	"snakegame/gametime"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The game times food with its clock, which stands still while paused
+func TestFoodWaitsOutAPause(t *testing.T) {
+	window := 50.0
+	clock := gametime.New(func() float64 { return window })
+	foods := NewFoodSet(1)
+	foods.Put(NormalFood, mgl32.Vec2{1, 1}, clock.Now())
+	foods.Put(BonusFood, mgl32.Vec2{2, 2}, clock.Now())
+	foods.Put(GoldenFood, mgl32.Vec2{3, 3}, clock.Now())
+
+	window += 1
+	clock.SetPaused(true)
+	window += 1000
+	foods.Expire(clock.Now())
+	if len(foods.Items) != 3 {
+		t.Fatalf("%d of 3 food left after a pause", len(foods.Items))
+	}
+
+	clock.SetPaused(false)
+	window += BonusFood.Type().Lifetime
+	foods.Expire(clock.Now())
+	if len(foods.Items) != 2 || foods.Count(BonusFood) != 0 {
+		t.Errorf("food left %v, want the bonus food gone once its time ran out", foods.Items)
+	}
+}
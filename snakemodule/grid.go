@@ -0,0 +1,32 @@
+package snakemodule
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Grid counts the segments on every cell they are on, so a collision
+// is a lookup rather than a distance measured against the whole body.
+// Only taken cells are kept, the board can be of any size.
+type Grid struct {
+	counts map[mgl32.Vec2]int
+}
+
+func NewGrid() Grid {
+	return Grid{counts: make(map[mgl32.Vec2]int)}
+}
+
+func (grid *Grid) Add(cell mgl32.Vec2) {
+	grid.counts[cell]++
+}
+
+func (grid *Grid) Remove(cell mgl32.Vec2) {
+	switch count := grid.counts[cell]; {
+	case count > 1:
+		grid.counts[cell] = count - 1
+	case count == 1:
+		delete(grid.counts, cell)
+	}
+}
+
+// Count returns the segments on the cell
+func (grid *Grid) Count(cell mgl32.Vec2) int {
+	return grid.counts[cell]
+}
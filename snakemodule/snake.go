@@ -1,9 +1,8 @@
 package snakemodule
 
 import (
-	"math/rand"
+	"math"
 	"snakegame/helpers"
-	"time"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -11,39 +10,46 @@ import (
 // Cell
 type Cell struct {
 	coords mgl32.Vec2
+	// Unit direction the snake moved in to reach the cell, and the one
+	// it left the previous cell in. They differ after a turning portal.
+	direction mgl32.Vec2
+	from      mgl32.Vec2
 }
 
 func (cell *Cell) GetCoords() mgl32.Vec2 {
 	return cell.coords
 }
 
-type Food struct {
-	cell Cell
-}
+// Kind of snake segment, used to pick its sprite
+type BodyPart int
 
-func (food *Food) SetPosition(possibleCells []int) {
-	if len(possibleCells) > 0 {
-		seedValue := time.Now().UnixNano()
-		seed := rand.NewSource(seedValue)
-		randNew := rand.New(seed)
-		chosenCell := possibleCells[randNew.Intn(len(possibleCells))]
-		x, y := helpers.IndexToCoords(chosenCell)
-		food.cell.coords = mgl32.Vec2{float32(x), float32(y)}
-	}
-}
+const (
+	Head BodyPart = iota
+	Straight
+	Corner
+	Tail
+)
 
-func (food *Food) Draw(
-	texture uint32,
-	draw func(texture uint32, vec mgl32.Vec2),
-) {
-	position := food.cell.coords
-	draw(texture, position)
+type Snake struct {
+	// Segments in a ring buffer, from the tail at body[tail] on to
+	// the head, so a step writes one cell instead of shifting them all
+	body   []Cell
+	tail   int
+	length int
+	// Where the head is drawn between two steps, collisions are
+	// decided on whole cells by the grid
+	front mgl32.Vec2
+	cells Grid
+	// Segments still to add, one on each move
+	growth int
 }
 
-type Snake struct {
-	body                  []Cell
-	front                 mgl32.Vec2
-	intersectionThreshold float32
+// Shortest snake left by shrinking, a head and a tail
+const minLength = 2
+
+// segment returns the i-th segment counting from the tail
+func (snake *Snake) segment(i int) *Cell {
+	return &snake.body[(snake.tail+i)%len(snake.body)]
 }
 
 func (snake *Snake) GetFront() mgl32.Vec2 {
@@ -55,74 +61,218 @@ func (snake *Snake) SetFront(vec mgl32.Vec2) {
 }
 
 func (snake *Snake) Move(vec mgl32.Vec2) {
-	snakeBody := snake.body
-	headIndex := len(snakeBody) - 1
-	headCoords := snakeBody[headIndex].coords
-
-	if vec.X() != headCoords.X() || vec.Y() != headCoords.Y() {
-		for i := 0; i < headIndex; i++ {
-			newCoords := snakeBody[i+1].coords
-			snakeBody[i].coords = newCoords
+	step := vec.Sub(snake.GetHead().coords)
+	if step.Len() > 0 {
+		step = step.Normalize()
+	}
+	snake.MoveVia(vec, step, step)
+}
+
+// MoveVia moves the head to vec, which needn't be next to it,
+// like when going through a portal. from is the direction the head
+// left its cell in, direction the one it arrives in.
+func (snake *Snake) MoveVia(vec, from, direction mgl32.Vec2) {
+	headCoords := snake.GetHead().coords
+	if vec.X() == headCoords.X() && vec.Y() == headCoords.Y() {
+		return
+	}
+	head := Cell{coords: vec, direction: direction, from: from}
+	snake.cells.Add(vec)
+	if snake.growth > 0 {
+		// The tail stays in place while the snake grows
+		snake.growth--
+		if snake.length == len(snake.body) {
+			snake.resize(2 * snake.length)
 		}
-		snakeBody[headIndex].coords = vec
+		snake.length++
+		*snake.segment(snake.length - 1) = head
+		return
 	}
+	// The head takes the slot after the old head, the tail moves up one
+	snake.cells.Remove(snake.segment(0).coords)
+	*snake.segment(snake.length) = head
+	snake.tail = (snake.tail + 1) % len(snake.body)
 }
 
-func (snake *Snake) Eat(food Food) bool {
-	snakeHead := snake.GetFront()
-	foodCoords := food.cell.GetCoords()
-	threshold := snake.intersectionThreshold
-	if helpers.Distance(snakeHead, foodCoords) < threshold {
-		snake.body = append(snake.body, food.cell)
-		return true
+// resize copies the segments into a buffer of the given size,
+// the tail going first
+func (snake *Snake) resize(size int) {
+	body := make([]Cell, size)
+	for i := 0; i < snake.length; i++ {
+		body[i] = *snake.segment(i)
 	}
-	return false
+	snake.body = body
+	snake.tail = 0
 }
 
-func (snake *Snake) Draw(
-	texture uint32,
-	draw func(texture uint32, vec mgl32.Vec2),
-) {
-	snakeBody := snake.body
-	for i := 0; i < len(snakeBody); i++ {
-		coords := snakeBody[i].coords
-		draw(texture, coords)
+// Eat takes the food on the cell of the front out of the set
+// and grows or shrinks the snake by its type, the front is set
+// to the next cell right before the step
+func (snake *Snake) Eat(foods *FoodSet) (Food, bool) {
+	for i, food := range foods.Items {
+		if food.cell.coords == snake.front {
+			foods.Remove(i)
+			growth := food.Kind.Type().Growth
+			if growth > 0 {
+				snake.growth += growth
+			} else {
+				snake.Shrink(-growth)
+			}
+			return food, true
+		}
 	}
+	return Food{}, false
+}
+
+// Shrink cuts segments off the tail, keeping at least a head and a tail
+func (snake *Snake) Shrink(segments int) {
+	if segments > snake.length-minLength {
+		segments = snake.length - minLength
+	}
+	for ; segments > 0; segments-- {
+		snake.cells.Remove(snake.segment(0).coords)
+		snake.tail = (snake.tail + 1) % len(snake.body)
+		snake.length--
+	}
+}
+
+// Length counts the segments, including the growth still to come
+func (snake *Snake) Length() int {
+	return snake.length + snake.growth
+}
+
+// Draw passes every segment with its sprite kind and rotation angle.
+// Sprites are expected to face +X: head looks right, tail continues right,
+// straight runs horizontally and corner joins the left and bottom edges.
+func (snake *Snake) Draw(draw func(part BodyPart, vec mgl32.Vec2, angle float32)) {
+	for i := 0; i < snake.length; i++ {
+		coords := snake.segment(i).coords
+		part, angle := snake.segmentSprite(i)
+		draw(part, coords, angle)
+	}
+}
+
+// segmentSprite works from the stored directions rather than the
+// neighbour coords, so a body split by a portal is drawn unbroken
+func (snake *Snake) segmentSprite(i int) (BodyPart, float32) {
+	headIndex := snake.length - 1
+	cell := snake.segment(i)
+	switch {
+	case i == headIndex && i == 0:
+		return Head, angle(snake.front.Sub(cell.coords))
+	case i == headIndex:
+		return Head, angle(cell.direction)
+	case i == 0:
+		return Tail, angle(snake.segment(i + 1).from)
+	}
+
+	toTail := cell.direction.Mul(-1)
+	toHead := snake.segment(i + 1).from
+	bend := toTail.Add(toHead)
+	if bend.Len() < 0.5 {
+		return Straight, angle(toHead)
+	}
+	// Corner sprite bends towards (-1, -1), i.e. angle -3π/4
+	return Corner, angle(bend) + 3*math.Pi/4
+}
+
+func angle(direction mgl32.Vec2) float32 {
+	return float32(math.Atan2(float64(direction.Y()), float64(direction.X())))
 }
 
 func (snake *Snake) GetHead() Cell {
-	snakeBody := snake.body
-	return snakeBody[len(snakeBody)-1]
+	return *snake.segment(snake.length - 1)
 }
 
+// CheckIntersection reports whether the front runs into the body, by
+// the rule of Blocked. A front still on the head cell only intersects
+// when another segment shares that cell.
 func (snake *Snake) CheckIntersection() bool {
-	snakeBody := snake.body
-	snakeHead := snake.GetFront()
-	threshold := snake.intersectionThreshold
-	for i := 0; i < len(snakeBody)-1; i++ {
-		if helpers.Distance(snakeHead, snakeBody[i].coords) < threshold {
-			return true
-		}
+	head := snake.GetHead()
+	if snake.front == head.coords {
+		return snake.cells.Count(head.coords) > 1
+	}
+	return snake.Blocked(snake.front)
+}
+
+// Occupies reports whether any segment is on the cell
+func (snake *Snake) Occupies(cell mgl32.Vec2) bool {
+	return snake.cells.Count(cell) > 0
+}
+
+// Blocked reports whether the head stepping onto the cell runs into
+// the body. The tail leaves its cell on the same step, so the head
+// may follow right behind it unless the snake is growing, or is just
+// a head and a tail and would be turning back on itself.
+func (snake *Snake) Blocked(cell mgl32.Vec2) bool {
+	count := snake.cells.Count(cell)
+	if count == 1 && snake.growth == 0 && snake.length > minLength && snake.segment(0).coords == cell {
+		return false
 	}
-	return false
+	return count > 0
 }
 
-func InitSnake(snakeLength int, intersectionThreshold float32) *Snake {
+func InitSnake(snakeLength int) *Snake {
 	var snake Snake
 	snake.body = make([]Cell, snakeLength)
+	snake.length = snakeLength
+	snake.cells = NewGrid()
 	for i := 0; i < snakeLength; i++ {
 		snake.body[i].coords = mgl32.Vec2{float32(i), float32(0)}
+		snake.body[i].direction = mgl32.Vec2{1, 0}
+		snake.body[i].from = mgl32.Vec2{1, 0}
+		snake.cells.Add(snake.body[i].coords)
 	}
 	snake.SetFront(snake.GetHead().coords)
-	snake.intersectionThreshold = intersectionThreshold
 	return &snake
 }
 
-func GetPossibleCells(snake *Snake, fieldCells []int) []int {
-	busyCells := make([]int, len(snake.body))
-	for i, val := range snake.body {
-		busyCells[i] = helpers.CoordsToIndex(int(val.coords.X()), int(val.coords.Y()))
+// NewSnake builds a snake from cells ordered from tail to head,
+// directions across gaps left by portals are taken from the cell before
+func NewSnake(cells []mgl32.Vec2) *Snake {
+	var snake Snake
+	snake.body = make([]Cell, len(cells))
+	snake.length = len(cells)
+	snake.cells = NewGrid()
+	direction := mgl32.Vec2{1, 0}
+	for i, coords := range cells {
+		if i > 0 {
+			step := coords.Sub(cells[i-1])
+			if length := step.Len(); length > 0 && length < 1.5 {
+				direction = step.Normalize()
+			}
+		}
+		snake.body[i] = Cell{coords: coords, direction: direction, from: direction}
+		snake.cells.Add(coords)
 	}
-	possibleCells := helpers.CellsDifference(fieldCells, busyCells)
+	if len(cells) > 1 {
+		snake.body[0].direction = snake.body[1].from
+		snake.body[0].from = snake.body[1].from
+	}
+	snake.SetFront(snake.GetHead().coords)
+	return &snake
+}
+
+// Cells returns a copy of the body coords from tail to head
+func (snake *Snake) Cells() []mgl32.Vec2 {
+	cells := make([]mgl32.Vec2, snake.length)
+	for i := range cells {
+		cells[i] = snake.segment(i).coords
+	}
+	return cells
+}
+
+func GetPossibleCells(snake *Snake, fieldCells []int) []int {
+	possibleCells := helpers.CellsDifference(fieldCells, snake.Indices())
 	return possibleCells
 }
+
+// Indices returns the field cells taken by the snake
+func (snake *Snake) Indices() []int {
+	indices := make([]int, snake.length)
+	for i := range indices {
+		coords := snake.segment(i).coords
+		indices[i] = helpers.CoordsToIndex(int(coords.X()), int(coords.Y()))
+	}
+	return indices
+}
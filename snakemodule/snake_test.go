@@ -0,0 +1,243 @@
+package snakemodule
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func cells(coords ...[2]float32) []mgl32.Vec2 {
+	list := make([]mgl32.Vec2, len(coords))
+	for i, c := range coords {
+		list[i] = mgl32.Vec2{c[0], c[1]}
+	}
+	return list
+}
+
+// A snake curled into a square, the head right next to the tail
+var square = [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+
+func TestBlocked(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   [][2]float32
+		growth int
+		cell   mgl32.Vec2
+		want   bool
+	}{
+		{"free cell", square, 0, mgl32.Vec2{0, 2}, false},
+		{"chasing the tail", square, 0, mgl32.Vec2{0, 0}, false},
+		{"growing into the tail", square, 1, mgl32.Vec2{0, 0}, true},
+		{"into the neck", square, 0, mgl32.Vec2{1, 1}, true},
+		{"into the middle", [][2]float32{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 1}}, 0, mgl32.Vec2{1, 0}, true},
+		{"reversing at length 2", [][2]float32{{0, 0}, {1, 0}}, 0, mgl32.Vec2{0, 0}, true},
+		{"length 3 chasing the tail", [][2]float32{{0, 0}, {1, 0}, {1, 1}}, 0, mgl32.Vec2{0, 0}, false},
+		{"off the board", square, 0, mgl32.Vec2{-1, 0}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snake := NewSnake(cells(test.body...))
+			snake.growth = test.growth
+			if got := snake.Blocked(test.cell); got != test.want {
+				t.Errorf("Blocked(%v) = %v, want %v", test.cell, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckIntersection(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   [][2]float32
+		growth int
+		front  mgl32.Vec2
+		want   bool
+	}{
+		{"front on the head", square, 0, mgl32.Vec2{0, 1}, false},
+		{"front on a free cell", square, 0, mgl32.Vec2{0, 2}, false},
+		{"front on the tail", square, 0, mgl32.Vec2{0, 0}, false},
+		{"front on the tail while growing", square, 1, mgl32.Vec2{0, 0}, true},
+		{"front on the neck", square, 0, mgl32.Vec2{1, 1}, true},
+		{"front back on the tail at length 2", [][2]float32{{0, 0}, {1, 0}}, 0, mgl32.Vec2{0, 0}, true},
+		{"head on the body", [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}, 0, mgl32.Vec2{0, 0}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snake := NewSnake(cells(test.body...))
+			snake.growth = test.growth
+			snake.SetFront(test.front)
+			if got := snake.CheckIntersection(); got != test.want {
+				t.Errorf("CheckIntersection() with the front on %v = %v, want %v", test.front, got, test.want)
+			}
+		})
+	}
+}
+
+func TestChasingTheTailMoves(t *testing.T) {
+	snake := NewSnake(cells(square...))
+	snake.Move(mgl32.Vec2{0, 0})
+	if got := snake.cells.Count(mgl32.Vec2{0, 0}); got != 1 {
+		t.Errorf("head on the old tail cell counted %d times, want 1", got)
+	}
+	want := cells([2]float32{1, 0}, [2]float32{1, 1}, [2]float32{0, 1}, [2]float32{0, 0})
+	for i, cell := range snake.Cells() {
+		if cell != want[i] {
+			t.Fatalf("cells = %v, want %v", snake.Cells(), want)
+		}
+	}
+}
+
+func TestGrowingKeepsTheTail(t *testing.T) {
+	snake := NewSnake(cells(square...))
+	foods := NewFoodSet(1)
+	foods.Put(NormalFood, mgl32.Vec2{0, 2}, 0)
+	snake.SetFront(mgl32.Vec2{0, 2})
+	if _, ok := snake.Eat(foods); !ok {
+		t.Fatal("food on the front cell wasn't eaten")
+	}
+	if !snake.Blocked(mgl32.Vec2{0, 0}) {
+		t.Error("the tail is free while the snake grows")
+	}
+	snake.Move(mgl32.Vec2{0, 2})
+	if snake.Length() != 5 || !snake.Occupies(mgl32.Vec2{0, 0}) {
+		t.Errorf("length %d, tail kept %v, want 5 and true", snake.Length(), snake.Occupies(mgl32.Vec2{0, 0}))
+	}
+}
+
+func TestGrid(t *testing.T) {
+	grid := NewGrid()
+	a, b := mgl32.Vec2{2, 3}, mgl32.Vec2{-1, 20}
+	grid.Add(a)
+	grid.Add(a)
+	grid.Add(b)
+	grid.Remove(a)
+	grid.Remove(mgl32.Vec2{5, 5})
+	tests := []struct {
+		cell mgl32.Vec2
+		want int
+	}{
+		{a, 1},
+		{b, 1},
+		{mgl32.Vec2{5, 5}, 0},
+	}
+	for _, test := range tests {
+		if got := grid.Count(test.cell); got != test.want {
+			t.Errorf("Count(%v) = %d, want %d", test.cell, got, test.want)
+		}
+	}
+}
+
+// checkModel compares the ring buffer and the grid with a plain
+// slice of the same cells, from tail to head
+func checkModel(t *testing.T, snake *Snake, model []mgl32.Vec2) {
+	t.Helper()
+	got := snake.Cells()
+	if len(got) != len(model) {
+		t.Fatalf("cells = %v, want %v", got, model)
+	}
+	counts := make(map[mgl32.Vec2]int)
+	for i, cell := range model {
+		if got[i] != cell {
+			t.Fatalf("cells = %v, want %v", got, model)
+		}
+		counts[cell]++
+	}
+	for cell, count := range counts {
+		if snake.cells.Count(cell) != count {
+			t.Fatalf("grid counts %v %d times, want %d", cell, snake.cells.Count(cell), count)
+		}
+	}
+	if len(snake.cells.counts) != len(counts) {
+		t.Fatalf("grid has %d cells, want %d", len(snake.cells.counts), len(counts))
+	}
+	head := snake.GetHead()
+	if head.coords != model[len(model)-1] {
+		t.Fatalf("head %v, want %v", head.coords, model[len(model)-1])
+	}
+}
+
+func TestRingBufferMatchesSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		// Steps in order: a positive number grows the snake by it,
+		// a negative one shrinks it, zero is a plain move
+		steps []int
+	}{
+		{"wraps around", []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{"grows when full", []int{1, 0, 0}},
+		{"grows after wrapping", []int{0, 0, 3, 0, 0, 0, 0, 0, 0}},
+		{"doubles twice", []int{5, 0, 0, 0, 0, 0, 10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{"shrinks", []int{-2, 0, 0, 0}},
+		{"shrinks after wrapping", []int{0, 0, 0, 0, -1, 0, 0, 0}},
+		{"shrinks to a head and a tail", []int{0, 0, -10, 0, 0, 0}},
+		{"shrinks then grows", []int{0, -2, 0, 4, 0, 0, 0, 0, 0, 0}},
+		{"grows then shrinks", []int{6, 0, 0, 0, -3, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := cells([2]float32{0, 0}, [2]float32{1, 0}, [2]float32{2, 0}, [2]float32{3, 0})
+			snake := NewSnake(model)
+			growth := 0
+			next := 0
+			for _, step := range test.steps {
+				switch {
+				case step > 0:
+					snake.growth += step
+					growth += step
+				case step < 0:
+					snake.Shrink(-step)
+					cut := -step
+					if cut > len(model)-minLength {
+						cut = len(model) - minLength
+					}
+					model = append([]mgl32.Vec2(nil), model[cut:]...)
+				default:
+					// Cells further on along a row, never taken twice
+					next++
+					cell := mgl32.Vec2{float32(3 + next), 0}
+					snake.Move(cell)
+					model = append(model, cell)
+					if growth > 0 {
+						growth--
+					} else {
+						model = model[1:]
+					}
+				}
+				checkModel(t, snake, model)
+			}
+			if snake.Length() != len(model)+growth {
+				t.Errorf("length %d, want %d", snake.Length(), len(model)+growth)
+			}
+		})
+	}
+}
+
+func TestRingBufferDoubles(t *testing.T) {
+	snake := NewSnake(cells([2]float32{0, 0}, [2]float32{1, 0}, [2]float32{2, 0}))
+	// Move the tail off the start of the buffer before growing
+	snake.Move(mgl32.Vec2{3, 0})
+	snake.growth = 1
+	snake.Move(mgl32.Vec2{4, 0})
+	if len(snake.body) != 6 || snake.tail != 0 {
+		t.Errorf("buffer of %d with the tail at %d, want 6 and 0", len(snake.body), snake.tail)
+	}
+	checkModel(t, snake, cells([2]float32{1, 0}, [2]float32{2, 0}, [2]float32{3, 0}, [2]float32{4, 0}))
+}
+
+func BenchmarkMove(b *testing.B) {
+	for _, length := range []int{100, 10000, 100000} {
+		b.Run(strconv.Itoa(length), func(b *testing.B) {
+			body := make([]mgl32.Vec2, length)
+			for i := range body {
+				body[i] = mgl32.Vec2{float32(i), 0}
+			}
+			snake := NewSnake(body)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Rows above the body, every cell a new one
+				snake.Move(mgl32.Vec2{float32(i & 0xffff), float32(1 + i>>16)})
+			}
+		})
+	}
+}
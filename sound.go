@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"snakegame/assets"
+	"snakegame/audio"
+	"snakegame/synth"
+)
+
+var audioOut = flag.String("audio-out", "", "record the game sound into this WAV file instead of playing it")
+var silent = flag.Bool("silent", false, "don't output any sound")
+
+const sampleRate = 44100
+
+var mixer = audio.NewMixer(sampleRate)
+var player *audio.Player
+
+// Sound effects by name, synthesized unless sounds/<name> overrides them
+var sounds = map[string]*audio.Sound{}
+
+// Eat sounds by snake length, the pitch rises as the snake grows
+var eatSounds = map[int]*audio.Sound{}
+
+// Music by level, loaded or synthesized on first use. A synthesized
+// tune keeps the step time it was made for and is made again when the
+// difficulty changes it, loaded music has a time window of 0.
+type levelTune struct {
+	sound      *audio.Sound
+	timeWindow float32
+}
+
+var levelMusic = map[int]levelTune{}
+var musicVoice *audio.Voice
+
+func initAudio() {
+	var output audio.Output = audio.NullOutput{}
+	switch {
+	case *silent:
+	case *audioOut != "":
+		wavOutput, err := audio.CreateWAVFile(*audioOut, sampleRate)
+		if err != nil {
+			log.Printf("warning: %v, sound is not recorded", err)
+		} else {
+			output = wavOutput
+		}
+	default:
+		speakers, err := audio.OpenSpeakers(sampleRate)
+		if err != nil {
+			log.Printf("warning: %v, the game runs silent", err)
+		} else {
+			output = speakers
+		}
+	}
+	synthesized := map[string]*audio.Sound{
+		"turn":     synth.Turn(sampleRate),
+		"levelup":  synth.LevelUp(sampleRate),
+		"death":    synth.Death(sampleRate),
+		"powerup":  synth.PowerUp(sampleRate),
+		"shield":   synth.ShieldHit(sampleRate),
+		"poison":   synth.Poison(sampleRate),
+		"teleport": synth.Teleport(sampleRate),
+		"crash":    synth.Crash(sampleRate),
+	}
+	for _, name := range []string{"eat", "turn", "levelup", "death", "powerup", "shield", "poison", "teleport", "crash"} {
+		sounds[name] = loadSound("sounds/" + name)
+		if sounds[name] == nil && synthesized[name] != nil {
+			sounds[name] = mixer.Prepare(synthesized[name])
+		}
+	}
+	mixer.SetBusGain(audio.Music, 0.5)
+	mixer.SetMuted(currentSettings.Muted)
+	player = audio.NewPlayer(mixer, output)
+}
+
+func closeAudio() {
+	err := player.Close()
+	if err != nil {
+		log.Printf("warning: audio output: %v", err)
+	}
+}
+
+// loadSound looks for a .wav and then an .ogg asset, nil if neither loads
+func loadSound(name string) *audio.Sound {
+	for _, extension := range []string{".wav", ".ogg"} {
+		path := name + extension
+		f, err := assets.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			log.Printf("warning: %v", err)
+			return nil
+		}
+		defer f.Close()
+		sound, err := audio.Decode(path, f)
+		if err != nil {
+			log.Printf("warning: %v", err)
+			return nil
+		}
+		return mixer.Prepare(sound)
+	}
+	return nil
+}
+
+// playEatSound uses the sounds/eat override if there is one
+func playEatSound(length int) {
+	if sounds["eat"] != nil {
+		playSound("eat")
+		return
+	}
+	sound, ok := eatSounds[length]
+	if !ok {
+		sound = mixer.Prepare(synth.Eat(sampleRate, length))
+		eatSounds[length] = sound
+	}
+	mixer.Play(sound, audio.SFX, 1)
+}
+
+func playSound(name string) {
+	if sound := sounds[name]; sound != nil {
+		mixer.Play(sound, audio.SFX, 1)
+	}
+}
+
+// playLevelMusic loops music/level<N> or music/default, without them
+// a tune is synthesized at the level speed
+func playLevelMusic(level int) {
+	stopMusic()
+	timeWindow := levelTimeWindow(level)
+	tune, ok := levelMusic[level]
+	if !ok || tune.timeWindow != 0 && tune.timeWindow != timeWindow {
+		tune = levelTune{sound: loadSound(fmt.Sprintf("music/level%d", level))}
+		if tune.sound == nil {
+			tune.sound = loadSound("music/default")
+		}
+		if tune.sound == nil {
+			tune.sound = mixer.Prepare(synth.Loop(sampleRate, level, float64(timeWindow)))
+			tune.timeWindow = timeWindow
+		}
+		levelMusic[level] = tune
+	}
+	musicVoice = mixer.Loop(tune.sound, audio.Music, 1)
+}
+
+func stopMusic() {
+	mixer.Stop(musicVoice)
+	musicVoice = nil
+}
+
+// toggleMute switches the sound and saves the choice
+func toggleMute() {
+	currentSettings.Muted = !mixer.Muted()
+	mixer.SetMuted(currentSettings.Muted)
+	err := currentSettings.Save()
+	if err != nil {
+		log.Printf("warning: settings not saved: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"snakegame/difficulty"
+	"snakegame/mode"
+)
+
+// Every segment grown on a level takes this share off the step time,
+// down to lengthRampFloor of the level step time
+const (
+	lengthRampStep  = 0.02
+	lengthRampFloor = 0.6
+)
+
+// Boost meter, full at 1, holding the boost key halves the step time
+// while it drains and it fills up again once the key is let go
+var boostMeter = 1.0
+var boostHeld = false
+
+// Meter used and regained per second
+const (
+	boostDrain  = 0.5
+	boostRefill = 0.125
+)
+
+// baseTimeWindow is the step time before the length ramp
+func baseTimeWindow() float32 {
+	switch {
+	case gameMode == mode.Daily:
+		return dailyChallenge.TimeWindow
+	case gameMode.Rules().SpeedRamp:
+		return getSurvivalTimeWindow(runTime)
+	}
+	return levelTimeWindow(gameLevel)
+}
+
+// rampTimeWindow speeds the step time up with the segments
+// the snake has grown since the level started
+func rampTimeWindow(window float32) float32 {
+	grown := snake.Length() - startLength()
+	if grown <= 0 {
+		return window
+	}
+	scale := math.Max(lengthRampFloor, math.Pow(1-lengthRampStep, float64(grown)))
+	return difficulty.Clamp(window * float32(scale))
+}
+
+func boosting() bool {
+	return boostHeld && boostMeter > 0
+}
+
+// updateBoost drains or refills the meter over the seconds of a step
+func updateBoost(seconds float64) {
+	if boosting() {
+		boostMeter = math.Max(0, boostMeter-boostDrain*seconds)
+	} else if !boostHeld {
+		boostMeter = math.Min(1, boostMeter+boostRefill*seconds)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"snakegame/events"
+	"snakegame/graphics"
+	"snakegame/mode"
+	"snakegame/scene"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var bus = events.NewBus()
+
+// subscribe connects the subsystems reacting to game events
+func subscribe() {
+	// Replay recording
+	bus.OnGameStarted(func(e events.GameStarted) {
+		recording.Reset()
+		recording.Record(e.Time, gameLevel, snake, foods, powerUps, hazards, rivals)
+	})
+	bus.OnSnakeMoved(func(e events.SnakeMoved) {
+		recording.Record(e.Time, gameLevel, snake, foods, powerUps, hazards, rivals)
+	})
+
+	// Progress
+	bus.OnLevelCompleted(func(e events.LevelCompleted) {
+		if gameMode.Rules().NoLevelCap {
+			return
+		}
+		carriedEffects = powerUps.SaveEffects(e.Time)
+		if gameMode == mode.Campaign {
+			saveProgress(e.NextLevel, carriedEffects)
+		}
+	})
+
+	// Leaderboard
+	bus.OnSnakeDied(func(e events.SnakeDied) {
+		endRun(e.Cause.String())
+	})
+	bus.OnTimeUp(func(e events.TimeUp) {
+		endRun("time")
+	})
+	bus.OnOutOfMoves(func(e events.OutOfMoves) {
+		endRun("moves")
+	})
+	bus.OnLevelCompleted(func(e events.LevelCompleted) {
+		if e.NextLevel == finalLevel() {
+			endRun("finished")
+		}
+	})
+
+	// Adaptive difficulty
+	bus.OnGameStarted(func(e events.GameStarted) {
+		levelStartedAt = e.Time
+		foodShownAt = e.Time
+	})
+	bus.OnFoodEaten(func(e events.FoodEaten) {
+		foodShownAt = e.Time
+	})
+	bus.OnDirectionChanged(func(e events.DirectionChanged) {
+		if adapting() && foodShownAt > 0 {
+			adaptive.Reacted((e.Time - foodShownAt) / float64(timeWindow))
+		}
+		foodShownAt = 0
+	})
+	bus.OnSnakeDied(func(e events.SnakeDied) {
+		if adapting() {
+			adaptive.Died(e.Time - levelStartedAt)
+		}
+	})
+	bus.OnLevelCompleted(func(e events.LevelCompleted) {
+		if adapting() {
+			adaptive.LevelCompleted()
+		}
+	})
+
+	// Feedback
+	bus.OnFoodEaten(func(e events.FoodEaten) {
+		animations.FoodEaten(e.Kind.String(), e.Position, e.Time)
+	})
+	bus.OnLevelCompleted(func(e events.LevelCompleted) {
+		center := float32(cellsNumber-1) / 2
+		animations.LevelUp(mgl32.Vec2{center, center}, e.Time)
+	})
+	bus.OnPowerUpCollected(func(e events.PowerUpCollected) {
+		animations.PowerUp(e.Kind.String(), e.Position, e.Time)
+	})
+	bus.OnShieldUsed(func(e events.ShieldUsed) {
+		graphics.Shake(0.01, 0.2)
+	})
+	bus.OnPoisonEaten(func(e events.PoisonEaten) {
+		animations.Poison(e.Kind.String(), e.Position, e.Time)
+	})
+	bus.OnTeleported(func(e events.Teleported) {
+		animations.Teleport(e.From, e.To, e.Time)
+	})
+	bus.OnRivalDied(func(e events.RivalDied) {
+		animations.RivalDeath(e.Position, scene.RivalTint.Vec3(), e.Time)
+	})
+	bus.OnSnakeDied(func(e events.SnakeDied) {
+		graphics.Shake(0.03, 0.4)
+		graphics.Flash(0.25)
+		animations.Death(e.Position, e.Time)
+	})
+
+	// Sound
+	bus.OnGameStarted(func(e events.GameStarted) {
+		playLevelMusic(e.Level)
+	})
+	bus.OnFoodEaten(func(e events.FoodEaten) {
+		playEatSound(snake.Length())
+	})
+	bus.OnDirectionChanged(func(e events.DirectionChanged) {
+		playSound("turn")
+	})
+	bus.OnLevelCompleted(func(e events.LevelCompleted) {
+		// Endless runs keep the board and the music
+		if !gameMode.Rules().NoLevelCap {
+			stopMusic()
+		}
+		playSound("levelup")
+	})
+	bus.OnTimeUp(func(e events.TimeUp) {
+		stopMusic()
+		playSound("levelup")
+	})
+	bus.OnOutOfMoves(func(e events.OutOfMoves) {
+		stopMusic()
+		playSound("death")
+	})
+	bus.OnPowerUpCollected(func(e events.PowerUpCollected) {
+		playSound("powerup")
+	})
+	bus.OnShieldUsed(func(e events.ShieldUsed) {
+		playSound("shield")
+	})
+	bus.OnPoisonEaten(func(e events.PoisonEaten) {
+		playSound("poison")
+	})
+	bus.OnTeleported(func(e events.Teleported) {
+		playSound("teleport")
+	})
+	bus.OnRivalDied(func(e events.RivalDied) {
+		playSound("crash")
+	})
+	bus.OnSnakeDied(func(e events.SnakeDied) {
+		stopMusic()
+		playSound("death")
+	})
+}
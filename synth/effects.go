@@ -0,0 +1,163 @@
+package synth
+
+import (
+	"math"
+	"snakegame/audio"
+)
+
+var blip = Envelope{Attack: 0.002, Decay: 0.03, Sustain: 0.6, Release: 0.04}
+
+// Eat is a short upward chirp, a semitone higher for every cell
+// of snake length, up to two octaves
+func Eat(sampleRate, length int) *audio.Sound {
+	semitones := math.Min(float64(length), 24)
+	base := Pitch(semitones - 9)
+	return Render(sampleRate, Note{
+		Wave:         Square,
+		Duty:         0.25,
+		Frequency:    base,
+		EndFrequency: base * 2,
+		Hold:         0.06,
+		Envelope:     blip,
+		Volume:       0.4,
+	})
+}
+
+func Turn(sampleRate int) *audio.Sound {
+	return Render(sampleRate, Note{
+		Wave:      Triangle,
+		Frequency: Pitch(-21),
+		Hold:      0.02,
+		Envelope:  Envelope{Attack: 0.001, Sustain: 1, Release: 0.015},
+		Volume:    0.35,
+	})
+}
+
+// LevelUp plays a rising major arpeggio
+func LevelUp(sampleRate int) *audio.Sound {
+	var notes []Note
+	for _, semitones := range []float64{3, 7, 10, 15} {
+		notes = append(notes, Note{
+			Wave:      Square,
+			Duty:      0.5,
+			Frequency: Pitch(semitones),
+			Hold:      0.1,
+			Envelope:  blip,
+			Volume:    0.3,
+		})
+	}
+	return Render(sampleRate, notes...)
+}
+
+// PowerUp is a fast rising sweep
+func PowerUp(sampleRate int) *audio.Sound {
+	return Render(sampleRate, Note{
+		Wave:         Triangle,
+		Frequency:    Pitch(-12),
+		EndFrequency: Pitch(12),
+		Hold:         0.25,
+		Envelope:     Envelope{Attack: 0.01, Sustain: 1, Release: 0.08},
+		Volume:       0.4,
+	})
+}
+
+// ShieldHit is a short metallic clank
+func ShieldHit(sampleRate int) *audio.Sound {
+	sound := Render(sampleRate, Note{
+		Wave:      Square,
+		Duty:      0.125,
+		Frequency: Pitch(7),
+		Hold:      0.05,
+		Envelope:  Envelope{Attack: 0.001, Decay: 0.05, Sustain: 0.3, Release: 0.1},
+		Volume:    0.3,
+	})
+	Note{
+		Wave:      Noise,
+		Frequency: 10000,
+		Hold:      0.04,
+		Envelope:  Envelope{Attack: 0.001, Decay: 0.04, Release: 0.02},
+		Volume:    0.3,
+	}.mix(sound.Samples, 0, sampleRate)
+	clip(sound.Samples)
+	return sound
+}
+
+// Poison is a sour wobble sliding down
+func Poison(sampleRate int) *audio.Sound {
+	var notes []Note
+	for _, semitones := range []float64{1, -1, -4} {
+		notes = append(notes, Note{
+			Wave:         Square,
+			Duty:         0.25,
+			Frequency:    Pitch(semitones),
+			EndFrequency: Pitch(semitones - 1),
+			Hold:         0.08,
+			Envelope:     blip,
+			Volume:       0.3,
+		})
+	}
+	return Render(sampleRate, notes...)
+}
+
+// Teleport is a warble sweeping up and back down
+func Teleport(sampleRate int) *audio.Sound {
+	return Render(sampleRate, Note{
+		Wave:         Triangle,
+		Frequency:    Pitch(-12),
+		EndFrequency: Pitch(19),
+		Hold:         0.12,
+		Envelope:     Envelope{Attack: 0.005, Sustain: 1},
+		Volume:       0.35,
+	}, Note{
+		Wave:         Triangle,
+		Frequency:    Pitch(19),
+		EndFrequency: Pitch(0),
+		Hold:         0.12,
+		Envelope:     Envelope{Sustain: 1, Release: 0.05},
+		Volume:       0.35,
+	})
+}
+
+// Death is a falling buzz over a noise crash
+func Death(sampleRate int) *audio.Sound {
+	sound := Render(sampleRate, Note{
+		Wave:         Square,
+		Duty:         0.5,
+		Frequency:    Pitch(-5),
+		EndFrequency: Pitch(-29),
+		Hold:         0.5,
+		Envelope:     Envelope{Attack: 0.005, Decay: 0.1, Sustain: 0.7, Release: 0.2},
+		Volume:       0.3,
+	})
+	Note{
+		Wave:      Noise,
+		Frequency: 8000,
+		Hold:      0.2,
+		Envelope:  Envelope{Attack: 0.001, Decay: 0.2, Sustain: 0, Release: 0.1},
+		Volume:    0.3,
+	}.mix(sound.Samples, 0, sampleRate)
+	clip(sound.Samples)
+	return sound
+}
+
+// Crash is a short noise thump for a rival hitting something
+func Crash(sampleRate int) *audio.Sound {
+	sound := Render(sampleRate, Note{
+		Wave:         Square,
+		Duty:         0.25,
+		Frequency:    Pitch(-17),
+		EndFrequency: Pitch(-29),
+		Hold:         0.15,
+		Envelope:     Envelope{Attack: 0.002, Decay: 0.1, Sustain: 0.4, Release: 0.1},
+		Volume:       0.25,
+	})
+	Note{
+		Wave:      Noise,
+		Frequency: 4000,
+		Hold:      0.1,
+		Envelope:  Envelope{Attack: 0.001, Decay: 0.1, Sustain: 0, Release: 0.05},
+		Volume:    0.25,
+	}.mix(sound.Samples, 0, sampleRate)
+	clip(sound.Samples)
+	return sound
+}
@@ -0,0 +1,77 @@
+package synth
+
+import (
+	"snakegame/audio"
+)
+
+// Steps in one loop, each one an eighth of a beat pair
+const loopSteps = 16
+
+// Shortest step, so very fast levels don't turn into a drone
+const minStep = 0.06
+
+// Bass and melody patterns in semitones, -1 rests
+var bassPatterns = [][loopSteps]float64{
+	{0, -1, 0, -1, 7, -1, 7, -1, 5, -1, 5, -1, 3, -1, 5, -1},
+	{0, -1, 12, -1, 0, -1, 12, -1, 3, -1, 15, -1, 5, -1, 17, -1},
+	{0, 0, -1, 0, 7, 7, -1, 7, 8, 8, -1, 8, 7, -1, 5, -1},
+}
+
+var melodyPatterns = [][loopSteps]float64{
+	{12, -1, 15, 17, -1, 19, -1, 17, 15, -1, 12, -1, 10, 12, -1, -1},
+	{19, 17, 15, -1, 12, -1, 15, -1, 17, 19, -1, 22, 19, -1, 17, -1},
+	{12, 12, 15, -1, 12, 10, -1, 7, 8, -1, 10, 12, -1, 15, 12, -1},
+}
+
+// Loop is a short background tune, the level picks the patterns
+// and a beat lasts beat seconds, the time the snake takes per cell
+func Loop(sampleRate, level int, beat float64) *audio.Sound {
+	step := beat / 2
+	if step < minStep {
+		step = minStep
+	}
+	if level < 0 {
+		level = -level
+	}
+	bass := bassPatterns[level%len(bassPatterns)]
+	melody := melodyPatterns[level%len(melodyPatterns)]
+	root := float64(level%5)*2 - 33
+
+	stepSamples := int(step * float64(sampleRate))
+	samples := make([]float32, stepSamples*loopSteps)
+	pluck := Envelope{Attack: 0.003, Decay: step / 2, Sustain: 0.5, Release: step / 4}
+	for i := 0; i < loopSteps; i++ {
+		start := i * stepSamples
+		if bass[i] >= 0 {
+			Note{
+				Wave:      Triangle,
+				Frequency: Pitch(root + bass[i]),
+				Hold:      step * 0.75,
+				Envelope:  pluck,
+				Volume:    0.35,
+			}.mix(samples, start, sampleRate)
+		}
+		if melody[i] >= 0 {
+			Note{
+				Wave:      Square,
+				Duty:      0.125,
+				Frequency: Pitch(root + 12 + melody[i]),
+				Hold:      step * 0.5,
+				Envelope:  pluck,
+				Volume:    0.15,
+			}.mix(samples, start, sampleRate)
+		}
+		// Hi-hat on the off beats
+		if i%2 == 1 {
+			Note{
+				Wave:      Noise,
+				Frequency: 12000,
+				Hold:      0.01,
+				Envelope:  Envelope{Attack: 0.001, Sustain: 1, Release: 0.03},
+				Volume:    0.1,
+			}.mix(samples, start, sampleRate)
+		}
+	}
+	clip(samples)
+	return &audio.Sound{SampleRate: sampleRate, Channels: 1, Samples: samples}
+}
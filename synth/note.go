@@ -0,0 +1,75 @@
+package synth
+
+import (
+	"math"
+	"snakegame/audio"
+)
+
+// Note is one oscillator sweeping from Frequency to EndFrequency
+// while it is held, 0 EndFrequency keeps the pitch
+type Note struct {
+	Wave         Wave
+	Duty         float64
+	Frequency    float64
+	EndFrequency float64
+	Hold         float64
+	Envelope     Envelope
+	Volume       float64
+}
+
+// Duration includes the release
+func (note Note) Duration() float64 {
+	return note.Hold + note.Envelope.Release
+}
+
+// mix adds the note into the mono samples from the given sample
+func (note Note) mix(samples []float32, start, sampleRate int) {
+	osc := newOscillator(note.Wave, note.Duty)
+	count := int(note.Duration() * float64(sampleRate))
+	endFrequency := note.EndFrequency
+	if endFrequency == 0 {
+		endFrequency = note.Frequency
+	}
+	for i := 0; i < count && start+i < len(samples); i++ {
+		t := float64(i) / float64(sampleRate)
+		// Sweep exponentially so it sounds even across octaves
+		progress := 1.0
+		if note.Hold > 0 {
+			progress = math.Min(1, t/note.Hold)
+		}
+		frequency := note.Frequency * math.Pow(endFrequency/note.Frequency, progress)
+		sample := osc.next(frequency, sampleRate) * note.Envelope.level(t, note.Hold) * note.Volume
+		samples[start+i] += float32(sample)
+	}
+}
+
+// Render synthesizes notes played one after another into a mono sound
+func Render(sampleRate int, notes ...Note) *audio.Sound {
+	var duration float64
+	for _, note := range notes {
+		duration += note.Duration()
+	}
+	samples := make([]float32, int(math.Ceil(duration*float64(sampleRate))))
+	start := 0.0
+	for _, note := range notes {
+		note.mix(samples, int(start*float64(sampleRate)), sampleRate)
+		start += note.Duration()
+	}
+	clip(samples)
+	return &audio.Sound{SampleRate: sampleRate, Channels: 1, Samples: samples}
+}
+
+func clip(samples []float32) {
+	for i, sample := range samples {
+		if sample > 1 {
+			samples[i] = 1
+		} else if sample < -1 {
+			samples[i] = -1
+		}
+	}
+}
+
+// Pitch returns the frequency semitones above A4
+func Pitch(semitones float64) float64 {
+	return 440 * math.Pow(2, semitones/12)
+}
@@ -0,0 +1,85 @@
+package synth
+
+import "math"
+
+type Wave int
+
+const (
+	Square Wave = iota
+	Triangle
+	Noise
+)
+
+// oscillator keeps the phase between samples, the noise comes from
+// a 15 bit LFSR like on the old sound chips, so it is deterministic
+type oscillator struct {
+	wave  Wave
+	duty  float64
+	phase float64
+	lfsr  uint16
+	noise float64
+}
+
+func newOscillator(wave Wave, duty float64) *oscillator {
+	if duty <= 0 || duty >= 1 {
+		duty = 0.5
+	}
+	return &oscillator{wave: wave, duty: duty, lfsr: 1, noise: 1}
+}
+
+// next returns the sample and advances by one sample at the frequency
+func (osc *oscillator) next(frequency float64, sampleRate int) float64 {
+	step := frequency / float64(sampleRate)
+	var sample float64
+	switch osc.wave {
+	case Square:
+		sample = -1
+		if osc.phase < osc.duty {
+			sample = 1
+		}
+	case Triangle:
+		sample = 4*math.Abs(osc.phase-0.5) - 1
+	case Noise:
+		sample = osc.noise
+	}
+	osc.phase += step
+	for osc.phase >= 1 {
+		osc.phase--
+		if osc.wave == Noise {
+			bit := (osc.lfsr ^ osc.lfsr>>1) & 1
+			osc.lfsr = osc.lfsr>>1 | bit<<14
+			osc.noise = float64(osc.lfsr&1)*2 - 1
+		}
+	}
+	return sample
+}
+
+// Envelope is an ADSR volume curve, times are in seconds
+type Envelope struct {
+	Attack  float64
+	Decay   float64
+	Sustain float64
+	Release float64
+}
+
+// level returns the volume at time t of a note held for hold seconds
+func (env Envelope) level(t, hold float64) float64 {
+	if t >= hold {
+		if env.Release <= 0 {
+			return 0
+		}
+		return math.Max(0, env.held(hold)*(1-(t-hold)/env.Release))
+	}
+	return env.held(t)
+}
+
+func (env Envelope) held(t float64) float64 {
+	switch {
+	case t < env.Attack:
+		return t / env.Attack
+	case t < env.Attack+env.Decay:
+		return 1 - (1-env.Sustain)*(t-env.Attack)/env.Decay
+	default:
+		return env.Sustain
+	}
+}
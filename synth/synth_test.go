@@ -0,0 +1,150 @@
+package synth
+
+import (
+	"math"
+	"snakegame/audio"
+	"snakegame/difficulty"
+	"testing"
+)
+
+const rate = 22050
+
+var effects = []struct {
+	name  string
+	sound func() *audio.Sound
+}{
+	{"eat", func() *audio.Sound { return Eat(rate, 5) }},
+	{"turn", func() *audio.Sound { return Turn(rate) }},
+	{"levelup", func() *audio.Sound { return LevelUp(rate) }},
+	{"powerup", func() *audio.Sound { return PowerUp(rate) }},
+	{"shield", func() *audio.Sound { return ShieldHit(rate) }},
+	{"poison", func() *audio.Sound { return Poison(rate) }},
+	{"teleport", func() *audio.Sound { return Teleport(rate) }},
+	{"death", func() *audio.Sound { return Death(rate) }},
+	{"crash", func() *audio.Sound { return Crash(rate) }},
+	{"loop", func() *audio.Sound { return Loop(rate, 2, 0.3) }},
+}
+
+func identical(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Float32bits(a[i]) != math.Float32bits(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEffectsRenderTheSameTwice(t *testing.T) {
+	for _, effect := range effects {
+		t.Run(effect.name, func(t *testing.T) {
+			first, second := effect.sound(), effect.sound()
+			if len(first.Samples) == 0 {
+				t.Fatal("no samples")
+			}
+			if !identical(first.Samples, second.Samples) {
+				t.Error("two renders differ")
+			}
+			for i, sample := range first.Samples {
+				if sample > 1 || sample < -1 {
+					t.Fatalf("sample %d is %v, out of -1..1", i, sample)
+				}
+			}
+		})
+	}
+}
+
+func TestNoiseIsRepeatable(t *testing.T) {
+	a, b := newOscillator(Noise, 0), newOscillator(Noise, 0)
+	seen := map[float64]int{}
+	for i := 0; i < 2000; i++ {
+		x, y := a.next(8000, rate), b.next(8000, rate)
+		if x != y {
+			t.Fatalf("sample %d differs: %v and %v", i, x, y)
+		}
+		seen[x]++
+	}
+	if len(seen) != 2 || seen[1] < 200 || seen[-1] < 200 {
+		t.Errorf("noise levels %v, want both 1 and -1 often", seen)
+	}
+}
+
+// crossings counts the rises through zero, twice as many for a
+// note an octave up
+func crossings(samples []float32) int {
+	count := 0
+	for i := 1; i < len(samples); i++ {
+		if samples[i-1] <= 0 && samples[i] > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func TestEatPitchRisesWithLength(t *testing.T) {
+	previous := 0
+	for _, length := range []int{1, 4, 8, 12, 16, 20, 24} {
+		sound := Eat(rate, length)
+		count := crossings(sound.Samples)
+		if count <= previous {
+			t.Errorf("length %d crosses zero %d times, no more than a shorter snake's %d", length, count, previous)
+		}
+		previous = count
+	}
+	// Two octaves up at most
+	if !identical(Eat(rate, 24).Samples, Eat(rate, 40).Samples) {
+		t.Error("the pitch keeps rising past 24 cells")
+	}
+	// 12 cells are an octave
+	low, high := crossings(Eat(rate, 4).Samples), crossings(Eat(rate, 16).Samples)
+	if ratio := float64(high) / float64(low); ratio < 1.8 || ratio > 2.2 {
+		t.Errorf("12 cells longer raise the pitch %.2f times, want about 2", ratio)
+	}
+}
+
+func TestLoopFollowsTheBeat(t *testing.T) {
+	tests := []struct {
+		beat float64
+		step float64
+	}{
+		{0.5, 0.25},
+		{0.3, 0.15},
+		{0.2, 0.1},
+		// Steps stop at minStep on the fastest levels
+		{0.1, minStep},
+		{0.05, minStep},
+	}
+	for _, test := range tests {
+		sound := Loop(rate, 0, test.beat)
+		want := int(test.step*rate) * loopSteps
+		if len(sound.Samples) != want || sound.Channels != 1 || sound.SampleRate != rate {
+			t.Errorf("beat %v: %d samples, want %d", test.beat, len(sound.Samples), want)
+		}
+		seconds := sound.Duration()
+		if math.Abs(seconds-test.step*loopSteps) > 0.01 {
+			t.Errorf("beat %v: the loop lasts %.3fs, want %.3fs", test.beat, seconds, test.step*loopSteps)
+		}
+	}
+}
+
+// The game passes the level step time as the beat
+func TestLoopFollowsTheLevelSpeed(t *testing.T) {
+	for preset := difficulty.Easy; preset < difficulty.Count; preset++ {
+		settings := preset.Settings()
+		previous := math.MaxInt32
+		for level := 0; level < 8; level++ {
+			window := float64(settings.TimeWindow(level))
+			length := len(Loop(rate, level, window).Samples)
+			step := math.Max(window/2, minStep)
+			if want := int(step*rate) * loopSteps; length != want {
+				t.Errorf("%s level %d: %d samples, want %d", preset, level, length, want)
+			}
+			if length > previous {
+				t.Errorf("%s level %d: the loop got longer on a faster level", preset, level)
+			}
+			previous = length
+		}
+	}
+}
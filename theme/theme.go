@@ -0,0 +1,213 @@
+package theme
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"snakegame/assets"
+	"snakegame/helpers"
+	"sort"
+	"strings"
+)
+
+// Name of the theme built from the embedded assets
+const Default = "default"
+
+// File describing a theme, at the root of its directory or zip
+const manifestName = "theme.json"
+
+// Atlas grid of the snake, food and wall sprites
+type AtlasManifest struct {
+	Image   string `json:"image"`
+	Columns int    `json:"columns"`
+	Rows    int    `json:"rows"`
+}
+
+// Manifest lists the theme images. Missing entries keep the defaults,
+// missing files are taken from the default assets.
+type Manifest struct {
+	Name       string   `json:"name"`
+	Background string   `json:"background"`
+	StartGame  string   `json:"startGame"`
+	GameOver   string   `json:"gameOver"`
+	Levels     []string `json:"levels"`
+	// Optional board background per level, Background is used otherwise
+	LevelBackgrounds []string          `json:"levelBackgrounds"`
+	Atlas            AtlasManifest     `json:"atlas"`
+	Sprites          map[string][2]int `json:"sprites"`
+	// RGB in [0, 1] of the window bars around the game area
+	LetterboxColor [3]float32 `json:"letterboxColor"`
+}
+
+func defaultManifest() Manifest {
+	return Manifest{
+		Name:       Default,
+		Background: "background.png",
+		StartGame:  "start_game.png",
+		GameOver:   "game_over.png",
+		Levels: []string{
+			"level_1.png",
+			"level_2.png",
+			"level_3.png",
+			"level_4.png",
+			"finish.png",
+		},
+		Atlas:          AtlasManifest{Image: "snake_atlas.png", Columns: 4, Rows: 5},
+		LetterboxColor: [3]float32{0.05, 0.05, 0.08},
+		Sprites: map[string][2]int{
+			"head":        {0, 0},
+			"straight":    {1, 0},
+			"corner":      {2, 0},
+			"tail":        {3, 0},
+			"food":        {0, 1},
+			"particle":    {1, 1},
+			"food_bonus":  {2, 1},
+			"food_golden": {3, 1},
+			"food_shrink": {0, 2},
+
+			"powerup_slow":   {1, 2},
+			"powerup_ghost":  {2, 2},
+			"powerup_magnet": {3, 2},
+			"powerup_shield": {0, 3},
+			"bar":            {1, 3},
+
+			"poison_shrink":  {2, 3},
+			"poison_reverse": {3, 3},
+			"spikes":         {0, 4},
+			"portal":         {1, 4},
+			"food_prey":      {2, 4},
+			"wall":           {3, 4},
+		},
+	}
+}
+
+type Theme struct {
+	Manifest Manifest
+	// Theme directory or zip file, empty for the default theme
+	Path  string
+	files fs.FS
+	zip   *zip.ReadCloser
+}
+
+// Dirs returns the directories searched for themes
+func Dirs() []string {
+	var dirs []string
+	for _, dir := range append(assets.SearchPath(), ".") {
+		dirs = append(dirs, filepath.Join(dir, "themes"))
+	}
+	return dirs
+}
+
+// List returns the names of all available themes, default first
+func List() []string {
+	seen := map[string]bool{Default: true}
+	var names []string
+	for _, dir := range Dirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := strings.TrimSuffix(entry.Name(), ".zip")
+			if !entry.IsDir() && name == entry.Name() {
+				continue
+			}
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return append([]string{Default}, names...)
+}
+
+// Load finds a theme directory or zip named name in Dirs
+func Load(name string) (*Theme, error) {
+	if name == "" || name == Default {
+		return &Theme{Manifest: defaultManifest()}, nil
+	}
+	for _, dir := range Dirs() {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return open(path, os.DirFS(path), nil)
+		}
+		if _, err := os.Stat(path + ".zip"); err == nil {
+			archive, err := zip.OpenReader(path + ".zip")
+			if err != nil {
+				return nil, fmt.Errorf("theme %s: %w", name, err)
+			}
+			return open(path+".zip", archive, archive)
+		}
+	}
+	return nil, fmt.Errorf("theme %s not found", name)
+}
+
+func open(path string, files fs.FS, archive *zip.ReadCloser) (*Theme, error) {
+	theme := Theme{Manifest: defaultManifest(), Path: path, files: files, zip: archive}
+	data, err := fs.ReadFile(files, manifestName)
+	if err != nil {
+		theme.Close()
+		return nil, fmt.Errorf("theme %s: %w", path, err)
+	}
+	// Unmarshal adds to the default sprites map,
+	// so a theme may override only some of them
+	err = json.Unmarshal(data, &theme.Manifest)
+	if err != nil {
+		theme.Close()
+		return nil, fmt.Errorf("theme %s: %w", path, err)
+	}
+	return &theme, nil
+}
+
+func (theme *Theme) Close() {
+	if theme.zip != nil {
+		theme.zip.Close()
+	}
+}
+
+// Open looks the file up in the theme, then in the default assets
+func (theme *Theme) Open(name string) (io.ReadCloser, error) {
+	if theme.files != nil {
+		f, err := theme.files.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return assets.Open(name)
+}
+
+// LoadImage logs a warning and returns a checkerboard
+// when the image can't be loaded
+func (theme *Theme) LoadImage(name string) ([]uint8, int32, int32) {
+	f, err := theme.Open(name)
+	if err == nil {
+		defer f.Close()
+		var imgBytes []uint8
+		var width, height int32
+		imgBytes, width, height, err = helpers.DecodeImage(f)
+		if err == nil {
+			return imgBytes, width, height
+		}
+	}
+	log.Printf("warning: theme %s: %s: %v, using a placeholder", theme.Manifest.Name, name, err)
+	return assets.Checkerboard(64, 8)
+}
+
+// LevelBackground returns the board background image of the level
+func (theme *Theme) LevelBackground(level int) string {
+	backgrounds := theme.Manifest.LevelBackgrounds
+	if level >= 0 && level < len(backgrounds) && backgrounds[level] != "" {
+		return backgrounds[level]
+	}
+	return theme.Manifest.Background
+}
@@ -0,0 +1,71 @@
+package theme
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher polls a theme on disk so artists see their changes live
+type Watcher struct {
+	path    string
+	changed int32
+	stop    chan struct{}
+}
+
+// Watch starts polling the theme files, the default theme never changes
+func Watch(theme *Theme, interval time.Duration) *Watcher {
+	watcher := Watcher{path: theme.Path, stop: make(chan struct{})}
+	if watcher.path == "" {
+		return &watcher
+	}
+	go func() {
+		lastModified := latestModTime(watcher.path)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-watcher.stop:
+				return
+			case <-ticker.C:
+				modified := latestModTime(watcher.path)
+				if modified.After(lastModified) {
+					lastModified = modified
+					atomic.StoreInt32(&watcher.changed, 1)
+				}
+			}
+		}
+	}()
+	return &watcher
+}
+
+// Changed reports whether files changed since the last call
+func (watcher *Watcher) Changed() bool {
+	return atomic.SwapInt32(&watcher.changed, 0) == 1
+}
+
+func (watcher *Watcher) Stop() {
+	close(watcher.stop)
+}
+
+func latestModTime(path string) time.Time {
+	var latest time.Time
+	filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := entry.Info()
+		if err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	if latest.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"snakegame/graphics"
+	"snakegame/scene"
+	"snakegame/settings"
+	"snakegame/theme"
+	"time"
+)
+
+var themeName = flag.String("theme", "", "theme name, overrides the saved setting")
+
+var currentSettings settings.Settings
+var loadedTheme string
+var themeWatcher *theme.Watcher
+
+// updateTheme reloads the scene when the level uses another theme
+// or the theme files changed on disk
+func updateTheme() {
+	name := currentSettings.ThemeFor(gameLevel)
+	if gameScene != nil && name == loadedTheme && !themeWatcher.Changed() {
+		return
+	}
+	th, err := theme.Load(name)
+	if err != nil {
+		log.Printf("warning: %v, using the default theme", err)
+		th, _ = theme.Load(theme.Default)
+	}
+	if gameScene != nil {
+		gameScene.Release()
+		gameScene.Theme.Close()
+		themeWatcher.Stop()
+	}
+	gameScene = scene.Load(graphics.Renderer{}, th, cellsNumber)
+	loadedTheme = name
+	themeWatcher = theme.Watch(th, time.Second)
+}
+
+// nextTheme selects the next available theme and saves the choice
+func nextTheme() {
+	names := theme.List()
+	current := currentSettings.Theme
+	if current == "" {
+		current = theme.Default
+	}
+	next := names[0]
+	for i, name := range names {
+		if name == current {
+			next = names[(i+1)%len(names)]
+		}
+	}
+	currentSettings.Theme = next
+	err := currentSettings.Save()
+	if err != nil {
+		log.Printf("warning: settings not saved: %v", err)
+	}
+	fmt.Println("theme:", next)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"snakegame/graphics"
+	"snakegame/settings"
+)
+
+// restoreWindow applies the window mode and geometry of the last run
+func restoreWindow() {
+	saved := currentSettings.Window
+	graphics.SetWindowedGeometry(graphics.WindowGeometry{
+		X:      saved.X,
+		Y:      saved.Y,
+		Width:  saved.Width,
+		Height: saved.Height,
+	})
+	mode := graphics.ParseWindowMode(saved.Mode)
+	if mode != graphics.Windowed || saved.Monitor != 0 {
+		graphics.SetWindowMode(mode, saved.Monitor)
+	}
+}
+
+func saveWindow() {
+	mode, monitor := graphics.GetWindowMode()
+	geometry := graphics.GetWindowedGeometry()
+	currentSettings.Window = settings.Window{
+		Mode:    mode.String(),
+		Monitor: monitor,
+		X:       geometry.X,
+		Y:       geometry.Y,
+		Width:   geometry.Width,
+		Height:  geometry.Height,
+	}
+	err := currentSettings.Save()
+	if err != nil {
+		log.Printf("warning: settings not saved: %v", err)
+	}
+}
+
+// cycleWindowMode goes windowed -> borderless -> fullscreen
+func cycleWindowMode() {
+	mode, monitor := graphics.GetWindowMode()
+	mode = (mode + 1) % (graphics.Fullscreen + 1)
+	graphics.SetWindowMode(mode, monitor)
+	fmt.Println("window mode:", mode)
+}
+
+func nextMonitor() {
+	names := graphics.MonitorNames()
+	if len(names) == 0 {
+		return
+	}
+	mode, monitor := graphics.GetWindowMode()
+	monitor = (monitor + 1) % len(names)
+	graphics.SetWindowMode(mode, monitor)
+	fmt.Println("monitor:", names[monitor])
+}